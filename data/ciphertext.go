@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// NormalizeCiphertext returns the components of a ciphertext of the
+// DDH based schemes, which are elements of the Z_P* group, in the
+// canonical minimal big-endian form, i.e. stripped of the leading
+// zeros of their P's byte width encoding, so that e.g. small
+// components take less storage. As the form depends only on the
+// value and P, equal components of DDH and Damgard ciphertexts over
+// the same group are normalized to the same bytes. It returns an
+// error if any of the components is not in [0, P).
+func NormalizeCiphertext(cipher Vector, P *big.Int) ([][]byte, error) {
+	components := make([][]byte, len(cipher))
+	for i, c := range cipher {
+		if c.Sign() < 0 || c.Cmp(P) >= 0 {
+			return nil, fmt.Errorf("component %d of the ciphertext should be in [0, P)", i)
+		}
+		components[i] = c.Bytes()
+	}
+
+	return components, nil
+}
+
+// DenormalizeCiphertext reconstructs the ciphertext from the
+// components returned by NormalizeCiphertext for the same P. It
+// returns an error if any of the components is longer than P's byte
+// width, is not in the minimal form, or is not in [0, P).
+func DenormalizeCiphertext(components [][]byte, P *big.Int) (Vector, error) {
+	width := (P.BitLen() + 7) / 8
+	cipher := make(Vector, len(components))
+	for i, b := range components {
+		if len(b) > width {
+			return nil, fmt.Errorf("component %d of the ciphertext should have at most %d bytes", i, width)
+		}
+		if len(b) > 0 && b[0] == 0 {
+			return nil, fmt.Errorf("component %d of the ciphertext is not in the minimal form", i)
+		}
+		c := new(big.Int).SetBytes(b)
+		if c.Cmp(P) >= 0 {
+			return nil, fmt.Errorf("component %d of the ciphertext should be in [0, P)", i)
+		}
+		cipher[i] = c
+	}
+
+	return cipher, nil
+}
+
+// CiphertextMarshalBinary serializes the ciphertext into a compact
+// binary form: a 4-byte big-endian count of the components, followed
+// by each component as a fixed-width big-endian block of width bytes,
+// which should be the byte length of the modulus P of the scheme. The
+// output thus takes 4 + len(cipher) * width bytes. It returns an error
+// if width is not positive, if there are more than 2^32 - 1
+// components, or if any of the components is negative or does not fit
+// into width bytes.
+func CiphertextMarshalBinary(cipher Vector, width int) ([]byte, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("width should be positive")
+	}
+	if uint64(len(cipher)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("ciphertext should have at most %d components", ^uint32(0))
+	}
+	buf := make([]byte, 4+len(cipher)*width)
+	binary.BigEndian.PutUint32(buf, uint32(len(cipher)))
+	for i, c := range cipher {
+		if c.Sign() < 0 || (c.BitLen()+7)/8 > width {
+			return nil, fmt.Errorf("component %d of the ciphertext should be a non-negative integer of at most %d bytes", i, width)
+		}
+		off := 4 + i*width
+		c.FillBytes(buf[off : off+width])
+	}
+
+	return buf, nil
+}
+
+// CiphertextUnmarshalBinary reconstructs the ciphertext serialized by
+// CiphertextMarshalBinary with the same width. It returns an error if
+// width is not positive or if the length of b does not match the
+// count of components it declares.
+func CiphertextUnmarshalBinary(b []byte, width int) (Vector, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("width should be positive")
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("serialized ciphertext should hold a 4-byte count")
+	}
+	// the count is checked against the length before allocating
+	n := uint64(binary.BigEndian.Uint32(b))
+	if uint64(len(b)-4) != n*uint64(width) {
+		return nil, fmt.Errorf("serialized ciphertext of %d components should have %d bytes, got %d",
+			n, 4+n*uint64(width), len(b))
+	}
+	cipher := make(Vector, n)
+	for i := range cipher {
+		off := 4 + i*width
+		cipher[i] = new(big.Int).SetBytes(b[off : off+width])
+	}
+
+	return cipher, nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCiphertext(t *testing.T) {
+	// 2^64 - 59 is a prime of 8 bytes
+	p, _ := new(big.Int).SetString("18446744073709551557", 10)
+	cipher := NewVector([]*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(256),
+		new(big.Int).Sub(p, big.NewInt(1)),
+	})
+
+	components, err := NormalizeCiphertext(cipher, p)
+	if err != nil {
+		t.Fatalf("Error during normalization: %v", err)
+	}
+	assert.Equal(t, [][]byte{{}, {1}, {1, 0}, new(big.Int).Sub(p, big.NewInt(1)).Bytes()}, components)
+
+	res, err := DenormalizeCiphertext(components, p)
+	if err != nil {
+		t.Fatalf("Error during denormalization: %v", err)
+	}
+	assert.Equal(t, len(cipher), len(res))
+	for i := range cipher {
+		assert.Equal(t, 0, cipher[i].Cmp(res[i]))
+	}
+
+	_, err = NormalizeCiphertext(NewVector([]*big.Int{p}), p)
+	assert.Error(t, err, "component not in [0, P) should be rejected")
+	_, err = NormalizeCiphertext(NewVector([]*big.Int{big.NewInt(-1)}), p)
+	assert.Error(t, err, "negative component should be rejected")
+
+	_, err = DenormalizeCiphertext([][]byte{{0, 1}}, p)
+	assert.Error(t, err, "leading zeros should be rejected")
+	_, err = DenormalizeCiphertext([][]byte{make([]byte, 9)}, p)
+	assert.Error(t, err, "too long component should be rejected")
+	_, err = DenormalizeCiphertext([][]byte{p.Bytes()}, p)
+	assert.Error(t, err, "component not in [0, P) should be rejected")
+}
+
+func TestCiphertextMarshalBinary(t *testing.T) {
+	cipher := NewVector([]*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(256),
+		new(big.Int).SetUint64(1<<64 - 1),
+	})
+
+	b, err := CiphertextMarshalBinary(cipher, 8)
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	assert.Equal(t, 4+4*8, len(b))
+	assert.Equal(t, []byte{0, 0, 0, 4}, b[:4])
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 1, 0}, b[4+2*8:4+3*8])
+
+	decoded, err := CiphertextUnmarshalBinary(b, 8)
+	if err != nil {
+		t.Fatalf("Error during deserialization: %v", err)
+	}
+	assert.Equal(t, len(cipher), len(decoded))
+	for i := range cipher {
+		assert.Equal(t, 0, cipher[i].Cmp(decoded[i]))
+	}
+
+	_, err = CiphertextMarshalBinary(cipher, 7)
+	assert.Error(t, err, "component wider than width should be rejected")
+	_, err = CiphertextMarshalBinary(NewVector([]*big.Int{big.NewInt(-1)}), 8)
+	assert.Error(t, err, "negative component should be rejected")
+
+	_, err = CiphertextUnmarshalBinary(b, 7)
+	assert.Error(t, err, "mismatching width should be rejected")
+	_, err = CiphertextUnmarshalBinary(b[:len(b)-1], 8)
+	assert.Error(t, err, "truncated input should be rejected")
+	_, err = CiphertextUnmarshalBinary([]byte{0xff, 0xff, 0xff, 0xff}, 8)
+	assert.Error(t, err, "count exceeding the input should be rejected")
+	_, err = CiphertextUnmarshalBinary([]byte{0, 0}, 8)
+	assert.Error(t, err, "input without a count should be rejected")
+}
@@ -157,14 +157,19 @@ func (m Matrix) Transpose() Matrix {
 	return mT
 }
 
-// CheckBound checks whether all matrix elements are strictly
-// smaller than the provided bound.
-// It returns error if at least one element is >= bound.
+// CheckBound checks whether all matrix elements are in the closed
+// interval [-bound, bound], like Vector.CheckBound.
+// It returns a *BoundViolationError if at least one element's absolute
+// value is > bound, reporting the row and column of the first such
+// element.
 func (m Matrix) CheckBound(bound *big.Int) error {
-	for _, v := range m {
+	for i, v := range m {
 		err := v.CheckBound(bound)
 		if err != nil {
-			return err
+			violation := err.(*BoundViolationError)
+			violation.Row = i
+			violation.inMatrix = true
+			return violation
 		}
 	}
 	return nil
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// SparseVector represents a vector by its nonzero coordinates,
+// mapping their indices to their values. Coordinates missing from
+// the map are zero.
+type SparseVector map[int]*big.Int
+
+// NewSparseVector returns the sparse representation of the vector v,
+// holding only its nonzero coordinates.
+func NewSparseVector(v Vector) SparseVector {
+	s := make(SparseVector)
+	for i, c := range v {
+		if c.Sign() != 0 {
+			s[i] = new(big.Int).Set(c)
+		}
+	}
+
+	return s
+}
+
+// Indices returns the indices of the coordinates held by s in
+// increasing order.
+func (s SparseVector) Indices() []int {
+	indices := make([]int, 0, len(s))
+	for i := range s {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	return indices
+}
+
+// CheckIndices checks whether all the indices of s are in [0, l).
+// It returns error otherwise.
+func (s SparseVector) CheckIndices(l int) error {
+	for i := range s {
+		if i < 0 || i >= l {
+			return fmt.Errorf("index %d of a sparse vector is not in [0, %d)", i, l)
+		}
+	}
+
+	return nil
+}
+
+// CheckBound checks whether the absolute values of all the coordinates
+// of s are not greater than the provided bound. It returns a
+// *BoundViolationError reporting the lowest index of a coordinate
+// exceeding the bound, if there is any.
+func (s SparseVector) CheckBound(bound *big.Int) error {
+	abs := new(big.Int)
+	for _, i := range s.Indices() {
+		abs.Abs(s[i])
+		if abs.Cmp(bound) > 0 {
+			return &BoundViolationError{
+				Index: i,
+				Value: new(big.Int).Set(s[i]),
+				Bound: new(big.Int).Set(bound),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Dense returns s as a Vector of length l. It returns an error if
+// any of the indices of s is not in [0, l).
+func (s SparseVector) Dense(l int) (Vector, error) {
+	if err := s.CheckIndices(l); err != nil {
+		return nil, err
+	}
+
+	v := NewConstantVector(l, big.NewInt(0))
+	for i, c := range s {
+		v[i].Set(c)
+	}
+
+	return v, nil
+}
+
+// DotDense calculates the inner product of s and the vector v,
+// touching only the coordinates of v at the indices of s. It returns
+// an error if any of the indices of s is not in [0, len(v)).
+func (s SparseVector) DotDense(v Vector) (*big.Int, error) {
+	if err := s.CheckIndices(len(v)); err != nil {
+		return nil, err
+	}
+
+	prod := new(big.Int)
+	for i, c := range s {
+		prod.Add(prod, new(big.Int).Mul(c, v[i]))
+	}
+
+	return prod, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseVector(t *testing.T) {
+	v := Vector{big.NewInt(0), big.NewInt(-3), big.NewInt(0), big.NewInt(7), big.NewInt(0)}
+	s := NewSparseVector(v)
+	assert.Equal(t, 2, len(s))
+	assert.Equal(t, []int{1, 3}, s.Indices())
+
+	dense, err := s.Dense(len(v))
+	assert.NoError(t, err)
+	assert.Equal(t, v, dense)
+	_, err = s.Dense(3)
+	assert.Error(t, err, "index out of range should be rejected")
+	assert.Error(t, SparseVector{-1: big.NewInt(1)}.CheckIndices(3))
+
+	w := Vector{big.NewInt(5), big.NewInt(2), big.NewInt(9), big.NewInt(-1), big.NewInt(4)}
+	prod, err := s.DotDense(w)
+	assert.NoError(t, err)
+	expected, _ := v.Dot(w)
+	assert.Equal(t, expected, prod)
+	_, err = s.DotDense(w[:3])
+	assert.Error(t, err)
+
+	assert.NoError(t, s.CheckBound(big.NewInt(7)))
+	var violation *BoundViolationError
+	assert.True(t, errors.As(s.CheckBound(big.NewInt(2)), &violation))
+	assert.Equal(t, 1, violation.Index, "lowest violating index should be reported")
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// EncodeCiphertextText encodes a ciphertext (or any other vector) as
+// base64 text over its binary encoding produced by MarshalVectors, so
+// that it can be embedded in text based formats such as JSON.
+// An empty vector is encoded as an empty string.
+func EncodeCiphertextText(cipher Vector) string {
+	if len(cipher) == 0 {
+		return ""
+	}
+	// MarshalVectors only fails for empty vectors
+	b, _ := MarshalVectors([]Vector{cipher})
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// DecodeCiphertextText decodes a ciphertext encoded by
+// EncodeCiphertextText. It accepts the expected number of components
+// of the ciphertext, e.g. l + 1 for a ciphertext of simple.DDH with
+// input vectors of length l. It returns an error if the text is
+// malformed or the number of components does not match.
+func DecodeCiphertextText(s string, length int) (Vector, error) {
+	if s == "" {
+		if length != 0 {
+			return nil, fmt.Errorf("ciphertext should have %d components, got 0", length)
+		}
+		return Vector{}, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := UnmarshalVectors(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(vs) != 1 {
+		return nil, fmt.Errorf("text should encode a single ciphertext")
+	}
+	if len(vs[0]) != length {
+		return nil, fmt.Errorf("ciphertext should have %d components, got %d", length, len(vs[0]))
+	}
+
+	return vs[0], nil
+}
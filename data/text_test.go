@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCiphertextText(t *testing.T) {
+	v := NewVector([]*big.Int{big.NewInt(123456789), big.NewInt(-1), big.NewInt(0)})
+
+	s := EncodeCiphertextText(v)
+	// the text survives a JSON round trip
+	j, err := json.Marshal(map[string]string{"cipher": s})
+	if err != nil {
+		t.Fatalf("Error during JSON encoding: %v", err)
+	}
+	var m map[string]string
+	if err := json.Unmarshal(j, &m); err != nil {
+		t.Fatalf("Error during JSON decoding: %v", err)
+	}
+
+	res, err := DecodeCiphertextText(m["cipher"], 3)
+	if err != nil {
+		t.Fatalf("Error during decoding: %v", err)
+	}
+	for i := range v {
+		assert.Equal(t, 0, v[i].Cmp(res[i]), "decoded ciphertext does not match")
+	}
+
+	_, err = DecodeCiphertextText(s, 4)
+	assert.Error(t, err, "wrong number of components should be rejected")
+	_, err = DecodeCiphertextText("not base64!", 3)
+	assert.Error(t, err, "malformed text should be rejected")
+	two, _ := MarshalVectors([]Vector{v, v})
+	_, err = DecodeCiphertextText(base64.StdEncoding.EncodeToString(two), 3)
+	assert.Error(t, err, "more than one vector should be rejected")
+
+	empty := EncodeCiphertextText(Vector{})
+	assert.Equal(t, "", empty)
+	res, err = DecodeCiphertextText(empty, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(res))
+	_, err = DecodeCiphertextText(empty, 3)
+	assert.Error(t, err, "wrong number of components should be rejected")
+}
@@ -142,15 +142,74 @@ func (v Vector) Mod(modulo *big.Int) Vector {
 	return NewVector(newCoords)
 }
 
-// CheckBound checks whether the absolute values of all vector elements
-// are strictly smaller than the provided bound.
-// It returns error if at least one element's absolute value is >= bound.
+// BoundViolationError is returned by the bound checks of vectors and
+// matrices when an element exceeds its bound. It describes the first
+// such element. The message of the error contains only the position
+// of the element, so that the plaintext does not end up in logs;
+// the element and the bound are available in Value and Bound.
+type BoundViolationError struct {
+	// Row is the row of the element in a matrix, 0 for vectors.
+	Row int
+	// Index is the index of the element in a vector, or its
+	// column in a matrix.
+	Index int
+	// Value is the element exceeding the bound.
+	Value *big.Int
+	// Bound is the bound of the element.
+	Bound *big.Int
+	// whether the element is in a matrix
+	inMatrix bool
+}
+
+func (e *BoundViolationError) Error() string {
+	if e.inMatrix {
+		return fmt.Sprintf("absolute value of element (%d, %d) of a matrix should not be greater than its bound", e.Row, e.Index)
+	}
+	return fmt.Sprintf("absolute value of coordinate %d of a vector should not be greater than its bound", e.Index)
+}
+
+// CheckBound checks whether all vector elements are in the closed
+// interval [-bound, bound], i.e. whether their absolute values are not
+// greater than bound; both -bound and bound are accepted. The schemes
+// check the input vectors of encryption and key derivation, and the
+// vectors y of decryption, with it.
+// It returns a *BoundViolationError if at least one element's absolute
+// value is > bound, reporting the index of the first such element.
 func (v Vector) CheckBound(bound *big.Int) error {
 	abs := new(big.Int)
-	for _, c := range v {
+	for i, c := range v {
 		abs.Abs(c)
 		if abs.Cmp(bound) > 0 {
-			return fmt.Errorf("all coordinates of a vector should not be greater than bound")
+			return &BoundViolationError{
+				Index: i,
+				Value: new(big.Int).Set(c),
+				Bound: new(big.Int).Set(bound),
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckBoundVector checks whether the absolute value of each vector
+// element is not greater than the corresponding element of bounds.
+// It returns error if the vectors differ in length, or a
+// *BoundViolationError if at least one element's absolute value is
+// > its bound, reporting the index of the first such element.
+func (v Vector) CheckBoundVector(bounds Vector) error {
+	if len(v) != len(bounds) {
+		return fmt.Errorf("vector and bounds should be of same length")
+	}
+
+	abs := new(big.Int)
+	for i, c := range v {
+		abs.Abs(c)
+		if abs.Cmp(bounds[i]) > 0 {
+			return &BoundViolationError{
+				Index: i,
+				Value: new(big.Int).Set(c),
+				Bound: new(big.Int).Set(bounds[i]),
+			}
 		}
 	}
 
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// MarshalVectors serializes vectors vs, which must all be of the same
+// length, into a compact columnar format. The format starts with a
+// header holding the number of vectors, their length and the byte
+// width w of the largest absolute value of a component (all encoded as
+// unsigned varints). It is followed by the components in column-major
+// order (first components of all vectors, then second components, ...),
+// each encoded as a sign byte and w bytes of the big-endian absolute
+// value.
+//
+// Since lengths are stored only once, this is considerably more compact
+// than serializing each vector on its own when storing many vectors of
+// the same length. It returns an error if the vectors differ in length
+// or are empty.
+func MarshalVectors(vs []Vector) ([]byte, error) {
+	l := 0
+	if len(vs) > 0 {
+		l = len(vs[0])
+	}
+	width := 0
+	for i, v := range vs {
+		if len(v) != l {
+			return nil, fmt.Errorf("vector %d should be of length %d, got %d", i, l, len(v))
+		}
+		for _, c := range v {
+			if n := (c.BitLen() + 7) / 8; n > width {
+				width = n
+			}
+		}
+	}
+
+	if len(vs) > 0 && l == 0 {
+		return nil, fmt.Errorf("vectors should not be empty")
+	}
+
+	buf := make([]byte, 3*binary.MaxVarintLen64, 3*binary.MaxVarintLen64+len(vs)*l*(width+1))
+	n := binary.PutUvarint(buf, uint64(len(vs)))
+	n += binary.PutUvarint(buf[n:], uint64(l))
+	n += binary.PutUvarint(buf[n:], uint64(width))
+	buf = buf[:n]
+	for j := 0; j < l; j++ {
+		for _, v := range vs {
+			var sign byte
+			if v[j].Sign() < 0 {
+				sign = 1
+			}
+			buf = append(buf, sign)
+			off := len(buf)
+			buf = append(buf, make([]byte, width)...)
+			v[j].FillBytes(buf[off:])
+		}
+	}
+
+	return buf, nil
+}
+
+// MarshalVectorsSize returns the length of the output of MarshalVectors
+// for count vectors of the given length, where the largest absolute
+// value of a component is width bytes long.
+func MarshalVectorsSize(count, length, width int) int {
+	var buf [binary.MaxVarintLen64]byte
+	size := 0
+	for _, v := range []int{count, length, width} {
+		size += binary.PutUvarint(buf[:], uint64(v))
+	}
+
+	return size + count*length*(width+1)
+}
+
+// UnmarshalVectors deserializes vectors serialized by MarshalVectors.
+// It returns an error if the input is malformed.
+func UnmarshalVectors(b []byte) ([]Vector, error) {
+	var header [3]uint64
+	for i := range header {
+		val, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed header of serialized vectors")
+		}
+		header[i] = val
+		b = b[n:]
+	}
+	count, l, width := header[0], header[1], header[2]
+
+	if (count == 0) != (l == 0) {
+		return nil, fmt.Errorf("malformed header of serialized vectors")
+	}
+	if count != 0 && (width >= uint64(len(b)) ||
+		count > uint64(len(b))/l/(width+1)) {
+		return nil, fmt.Errorf("serialized vectors are truncated")
+	}
+	if uint64(len(b)) != count*l*(width+1) {
+		return nil, fmt.Errorf("length of serialized vectors does not match the header")
+	}
+
+	vs := make([]Vector, count)
+	for i := range vs {
+		vs[i] = make(Vector, l)
+	}
+	for j := uint64(0); j < l; j++ {
+		for _, v := range vs {
+			sign := b[0]
+			if sign > 1 {
+				return nil, fmt.Errorf("malformed sign of a serialized component")
+			}
+			v[j] = new(big.Int).SetBytes(b[1 : width+1])
+			if sign == 1 {
+				v[j].Neg(v[j])
+			}
+			b = b[width+1:]
+		}
+	}
+
+	return vs, nil
+}
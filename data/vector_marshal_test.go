@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVector_MarshalVectors(t *testing.T) {
+	vs := []Vector{
+		NewVector([]*big.Int{big.NewInt(1), big.NewInt(-300), big.NewInt(0)}),
+		NewVector([]*big.Int{big.NewInt(-1), big.NewInt(65536), big.NewInt(7)}),
+	}
+
+	b, err := MarshalVectors(vs)
+	if err != nil {
+		t.Fatalf("Error during vector serialization: %v", err)
+	}
+	// header of 3 bytes, 6 components of a sign byte and 3 bytes
+	assert.Equal(t, 3+6*4, len(b))
+	assert.Equal(t, len(b), MarshalVectorsSize(2, 3, 3))
+
+	res, err := UnmarshalVectors(b)
+	if err != nil {
+		t.Fatalf("Error during vector deserialization: %v", err)
+	}
+	assert.Equal(t, len(vs), len(res))
+	for i := range vs {
+		assert.Equal(t, len(vs[i]), len(res[i]))
+		for j := range vs[i] {
+			assert.Equal(t, 0, vs[i][j].Cmp(res[i][j]), "deserialized vector does not match")
+		}
+	}
+
+	_, err = UnmarshalVectors(b[:len(b)-1])
+	assert.Error(t, err, "truncated input should be rejected")
+	_, err = UnmarshalVectors(append(b, 0))
+	assert.Error(t, err, "trailing data should be rejected")
+
+	ragged := []Vector{vs[0], NewVector([]*big.Int{big.NewInt(1)})}
+	_, err = MarshalVectors(ragged)
+	assert.Error(t, err, "vectors of different lengths should be rejected")
+
+	b, err = MarshalVectors(nil)
+	if err != nil {
+		t.Fatalf("Error during vector serialization: %v", err)
+	}
+	res, err = UnmarshalVectors(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(res))
+}
+
+func TestVector_UnmarshalVectorsMalformed(t *testing.T) {
+	for _, b := range [][]byte{
+		{},
+		{2, 0, 0},
+		{0, 2, 0},
+		{1, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 1, 1, 0, 1},
+		{1, 1, 1, 2, 5},
+	} {
+		_, err := UnmarshalVectors(b)
+		assert.Error(t, err, "malformed input %v should be rejected", b)
+	}
+
+	_, err := MarshalVectors([]Vector{{}})
+	assert.Error(t, err, "empty vectors should be rejected")
+}
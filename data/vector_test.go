@@ -17,6 +17,7 @@
 package data
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -87,3 +88,84 @@ func TestVecor_Tensor(t *testing.T) {
 
 	assert.Equal(t, prodExpected, prod, "tensor product of vectors does not work correctly")
 }
+
+func TestVector_CheckBoundVector(t *testing.T) {
+	bounds := Vector{big.NewInt(10), big.NewInt(1), big.NewInt(100)}
+
+	inBound := Vector{big.NewInt(-10), big.NewInt(1), big.NewInt(-57)}
+	assert.NoError(t, inBound.CheckBoundVector(bounds))
+
+	outOfBound := Vector{big.NewInt(3), big.NewInt(-2), big.NewInt(101)}
+	err := outOfBound.CheckBoundVector(bounds)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "coordinate 1")
+
+	lastOutOfBound := Vector{big.NewInt(3), big.NewInt(0), big.NewInt(-101)}
+	err = lastOutOfBound.CheckBoundVector(bounds)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "coordinate 2")
+
+	assert.Error(t, inBound[:2].CheckBoundVector(bounds))
+
+	var violation *BoundViolationError
+	assert.True(t, errors.As(lastOutOfBound.CheckBoundVector(bounds), &violation))
+	assert.Equal(t, 2, violation.Index)
+	assert.Equal(t, 0, violation.Value.Cmp(big.NewInt(-101)))
+	assert.Equal(t, 0, violation.Bound.Cmp(big.NewInt(100)))
+}
+
+func TestVector_CheckBound(t *testing.T) {
+	bound := big.NewInt(10)
+	assert.NoError(t, Vector{big.NewInt(-10), big.NewInt(10), big.NewInt(0)}.CheckBound(bound))
+
+	v := Vector{big.NewInt(3), big.NewInt(-11), big.NewInt(12)}
+	err := v.CheckBound(bound)
+	var violation *BoundViolationError
+	if !errors.As(err, &violation) {
+		t.Fatalf("expected a bound violation, got %v", err)
+	}
+	assert.Equal(t, 1, violation.Index, "first violating coordinate should be reported")
+	assert.Equal(t, 0, violation.Value.Cmp(big.NewInt(-11)))
+	assert.Equal(t, 0, violation.Bound.Cmp(bound))
+	assert.Contains(t, err.Error(), "coordinate 1")
+	assert.NotContains(t, err.Error(), "11", "value should not be in the message")
+
+	// the error does not alias the inputs
+	v[1].SetInt64(0)
+	assert.Equal(t, 0, violation.Value.Cmp(big.NewInt(-11)))
+
+	m := Matrix{Vector{big.NewInt(1), big.NewInt(2)}, Vector{big.NewInt(3), big.NewInt(40)}}
+	err = m.CheckBound(bound)
+	if !errors.As(err, &violation) {
+		t.Fatalf("expected a bound violation, got %v", err)
+	}
+	assert.Equal(t, 1, violation.Row)
+	assert.Equal(t, 1, violation.Index)
+	assert.Contains(t, err.Error(), "(1, 1)")
+}
+
+func TestVector_CheckBoundEdges(t *testing.T) {
+	for _, bound := range []*big.Int{
+		big.NewInt(1),
+		big.NewInt(10),
+		new(big.Int).Lsh(big.NewInt(1), 100),
+	} {
+		one := big.NewInt(1)
+		negBound := new(big.Int).Neg(bound)
+		inBound := []*big.Int{negBound, bound, new(big.Int).Add(negBound, one), new(big.Int).Sub(bound, one)}
+		for _, c := range inBound {
+			assert.NoError(t, Vector{big.NewInt(0), c}.CheckBound(bound), "%s should be within [-%s, %s]", c, bound, bound)
+			assert.NoError(t, Matrix{Vector{c}, Vector{big.NewInt(0)}}.CheckBound(bound))
+		}
+
+		outOfBound := []*big.Int{new(big.Int).Sub(negBound, one), new(big.Int).Add(bound, one)}
+		for _, c := range outOfBound {
+			var violation *BoundViolationError
+			assert.True(t, errors.As(Vector{big.NewInt(0), c}.CheckBound(bound), &violation),
+				"%s should be outside of [-%s, %s]", c, bound, bound)
+			assert.Equal(t, 1, violation.Index)
+			assert.True(t, errors.As(Matrix{Vector{big.NewInt(0)}, Vector{c}}.CheckBound(bound), &violation))
+			assert.Equal(t, 1, violation.Row)
+		}
+	}
+}
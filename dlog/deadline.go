@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// TimeoutError is returned by SolveDeadline when the deadline passed
+// before the discrete logarithm was found or the whole interval was
+// searched.
+type TimeoutError struct {
+	// all x with |x| < Searched were searched without finding
+	// the solution, 0 if the search did not start yet
+	Searched *big.Int
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("deadline exceeded before finding the discrete logarithm, searched |x| < %s", e.Searched)
+}
+
+// SolveDeadline returns x with |x| <= bound such that g^x = h (mod P)
+// using the baby-step giant-step method of Table, unless the deadline
+// passes first. The giant steps proceed from small to large absolute
+// values of x, alternating positive and negative ones, and the
+// deadline is checked regularly both while the baby steps are computed
+// and during the search.
+//
+// It returns a *TimeoutError holding how far the search got if the
+// deadline passed, and a different error if bound exceeds 2^48, if h
+// is not invertible modulo P, or if there is no such x.
+func SolveDeadline(h, g, P, bound *big.Int, deadline time.Time) (*big.Int, error) {
+	return dlog.SolveStop(h, g, P, bound, true, func(searched *big.Int) error {
+		if !time.Now().After(deadline) {
+			return nil
+		}
+		return &TimeoutError{Searched: searched}
+	})
+}
+
+// SolveContext returns x with |x| <= bound such that g^x = h (mod P)
+// using the baby-step giant-step method, like SolveDeadline, but stops
+// once ctx is done instead. The context is checked every 1024 steps,
+// thus the search returns ctx.Err() promptly once ctx is cancelled or
+// its deadline passes. It returns a different error if bound exceeds
+// 2^48, if h is not invertible modulo P, or if there is no such x.
+func SolveContext(ctx context.Context, h, g, P, bound *big.Int) (*big.Int, error) {
+	return dlog.SolveStop(h, g, P, bound, true, func(*big.Int) error {
+		return ctx.Err()
+	})
+}
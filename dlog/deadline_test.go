@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolveDeadline(t *testing.T) {
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	for _, x := range []int64{-10000, -1, 0, 7, 9999, 10000} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := dlog.SolveDeadline(h, key.G, key.P, big.NewInt(10000), deadline)
+		if err != nil {
+			t.Fatalf("Error during computing the discrete logarithm: %v", err)
+		}
+		assert.Equal(t, x, res.Int64())
+	}
+
+	h := internal.ModExp(key.G, big.NewInt(10001), key.P)
+	_, err = dlog.SolveDeadline(h, key.G, key.P, big.NewInt(10000), deadline)
+	var timeout *dlog.TimeoutError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &timeout), "missing solution should not be reported as a timeout")
+
+	_, err = dlog.SolveDeadline(big.NewInt(0), key.G, key.P, big.NewInt(10000), deadline)
+	assert.Error(t, err, "element that is not invertible should be rejected")
+
+	// the deadline passed before the search started
+	_, err = dlog.SolveDeadline(h, key.G, key.P, big.NewInt(10000), time.Now().Add(-time.Second))
+	if assert.True(t, errors.As(err, &timeout), "expected *TimeoutError, got %v", err) {
+		assert.Equal(t, int64(0), timeout.Searched.Int64())
+	}
+
+	// the deadline passes during the search of a large interval
+	bound := new(big.Int).Lsh(big.NewInt(1), 44)
+	_, err = dlog.SolveDeadline(h, key.G, key.P, bound, time.Now().Add(50*time.Millisecond))
+	if assert.True(t, errors.As(err, &timeout), "expected *TimeoutError, got %v", err) {
+		assert.True(t, timeout.Searched.Cmp(bound) <= 0)
+	}
+}
+
+func TestSolveContext(t *testing.T) {
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	for _, x := range []int64{-10000, -1, 0, 7, 10000} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := dlog.SolveContext(context.Background(), h, key.G, key.P, big.NewInt(10000))
+		if err != nil {
+			t.Fatalf("Error during computing the discrete logarithm: %v", err)
+		}
+		assert.Equal(t, x, res.Int64())
+	}
+
+	h := internal.ModExp(key.G, big.NewInt(10001), key.P)
+	_, err = dlog.SolveContext(context.Background(), h, key.G, key.P, big.NewInt(10000))
+	assert.Error(t, err)
+	_, err = dlog.SolveContext(context.Background(), big.NewInt(0), key.G, key.P, big.NewInt(10000))
+	assert.Error(t, err, "element that is not invertible should be rejected")
+
+	// the context was cancelled before the search started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = dlog.SolveContext(ctx, h, key.G, key.P, big.NewInt(10000))
+	assert.Equal(t, context.Canceled, err)
+
+	// the context is cancelled during the search of a large interval
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = dlog.SolveContext(ctx, h, key.G, key.P, new(big.Int).Lsh(big.NewInt(1), 44))
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "search should stop promptly")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = dlog.SolveContext(ctx, h, key.G, key.P, new(big.Int).Lsh(big.NewInt(1), 44))
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
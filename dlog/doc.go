@@ -0,0 +1,23 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dlog lets users of the DDH based schemes choose how the
+// discrete logarithm is computed during decryption. The schemes accept
+// a Solver, e.g. one delegating the computation to a hardware
+// accelerated service, and by default use BabyStepGiantStepSolver.
+// SharedTable is a Solver with the baby steps in a memory-mapped file,
+// shared by the processes decrypting with the same parameters.
+package dlog
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// SearchRange searches for x in [lo, hi] such that g^x = h (mod P),
+// using the baby-step giant-step method of Table on the range only.
+// It returns the smallest such x and true if there is one, and false
+// otherwise.
+//
+// It allows splitting the computation of a discrete logarithm over
+// workers: the interval of possible solutions is divided into ranges,
+// each worker searches its range, and the solution is taken from the
+// worker reporting a match. Since a worker may be faulty, the solution
+// should be verified, e.g. with FinishDecryptWithExponent of the
+// schemes.
+//
+// It returns an error if lo > hi or the range holds more than
+// 2^48 values.
+func SearchRange(h, g, P, lo, hi *big.Int) (*big.Int, bool, error) {
+	if lo.Cmp(hi) > 0 {
+		return nil, false, fmt.Errorf("lower end of the range should not exceed the upper end")
+	}
+	// number of values in the range
+	n := new(big.Int).Sub(hi, lo)
+	n.Add(n, big.NewInt(1))
+	if n.Cmp(dlog.MaxBound) > 0 {
+		return nil, false, fmt.Errorf("range should hold at most %s values", dlog.MaxBound)
+	}
+
+	// g^x = h with x in [lo, hi] if g^(x - lo) = h * g^-lo with
+	// x - lo in [0, n - 1]
+	y := internal.ModExp(g, new(big.Int).Neg(lo), P)
+	y.Mod(y.Mul(y, h), P)
+	x, err := dlog.SolveStop(y, g, P, n.Sub(n, big.NewInt(1)), false, nil)
+	if errors.Is(err, dlog.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return x.Add(x, lo), true, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRange(t *testing.T) {
+	key, err := keygen.NewElGamal(20)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	for _, x := range []int64{-1000, -1, 0, 7, 1000} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		// split [-1000, 1000] into ranges of 300 values
+		var found []*big.Int
+		for lo := int64(-1000); lo <= 1000; lo += 300 {
+			hi := lo + 299
+			if hi > 1000 {
+				hi = 1000
+			}
+			res, ok, err := dlog.SearchRange(h, key.G, key.P, big.NewInt(lo), big.NewInt(hi))
+			if err != nil {
+				t.Fatalf("Error during search: %v", err)
+			}
+			if ok {
+				assert.True(t, res.Int64() >= lo && res.Int64() <= hi, "result should be in the range")
+				found = append(found, res)
+			}
+		}
+		assert.Equal(t, 1, len(found), "exactly one range should hold the solution")
+		assert.Equal(t, x, found[0].Int64())
+	}
+
+	h := internal.ModExp(key.G, big.NewInt(5), key.P)
+	_, ok, err := dlog.SearchRange(h, key.G, key.P, big.NewInt(6), big.NewInt(6))
+	assert.NoError(t, err)
+	assert.False(t, ok, "solution outside the range should not be found")
+	res, ok, err := dlog.SearchRange(h, key.G, key.P, big.NewInt(5), big.NewInt(5))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), res.Int64())
+
+	// the ends of the range are included
+	for _, r := range [][2]int64{{5, 1000}, {-1000, 5}} {
+		res, ok, err = dlog.SearchRange(h, key.G, key.P, big.NewInt(r[0]), big.NewInt(r[1]))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(5), res.Int64())
+	}
+	// an element that is not a power of g is not found, without an error
+	_, ok, err = dlog.SearchRange(big.NewInt(0), key.G, key.P, big.NewInt(-1000), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = dlog.SearchRange(h, key.G, key.P, big.NewInt(1), big.NewInt(0))
+	assert.Error(t, err, "empty range should be rejected")
+	hi := new(big.Int).Lsh(big.NewInt(1), 48)
+	_, _, err = dlog.SearchRange(h, key.G, key.P, big.NewInt(-1), hi)
+	assert.Error(t, err, "too wide range should be rejected")
+}
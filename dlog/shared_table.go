@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// The shared table file holds a header and an open addressing hash
+// table of baby steps:
+//
+//	magic "GOFEDLT1"
+//	len(P) uint32, P, len(g) uint32, g
+//	m uint64, number of slots n uint64 (a power of two)
+//	n slots of (fnv-1a hash of g^j uint64, j + 1 uint64)
+//
+// with all the integers big-endian and an empty slot holding j + 1 = 0.
+var sharedTableMagic = []byte("GOFEDLT1")
+
+const (
+	sharedTableSlotSize = 16
+	// limits the length of P and g in the header
+	sharedTableMaxIntLen = 1 << 16
+)
+
+// maxSharedTableSteps limits the number of baby steps m, so that
+// m² <= MaxBound of the discrete logarithm computation.
+var maxSharedTableSteps = new(big.Int).Sqrt(dlog.MaxBound).Uint64()
+
+// SharedTable is a table of baby steps g^j mod P for j in [0, m),
+// stored in a file written by WriteSharedTable and memory-mapped
+// read-only by OpenSharedTable, so that processes on a host decrypting
+// with the same parameters share a single copy of the table in the
+// page cache. Lookups are performed directly on the mapped file.
+//
+// SharedTable is a Solver of discrete logarithms x with
+// |x| < m² for the generator and group of the table. Every
+// solution is verified, so a corrupted file may cause the solution
+// not to be found, but never a wrong solution. It is safe for
+// concurrent use until it is closed.
+type SharedTable struct {
+	data  []byte
+	unmap func() error
+	p     *big.Int
+	g     *big.Int
+	m     uint64
+	// the slots of the table in data
+	slots []byte
+	mask  uint64
+}
+
+// WriteSharedTable computes the baby steps of generator g in Z_P
+// needed to compute discrete logarithms x with |x| <= bound, and
+// writes them to the file at path in the format of SharedTable. The
+// file is written to a temporary file first and then renamed, so that
+// processes opening path never see a partially written table.
+// It returns an error if bound exceeds 2^48 or if the file could not
+// be written.
+func WriteSharedTable(path string, g, P, bound *big.Int) error {
+	if bound.Sign() < 0 || bound.Cmp(dlog.MaxBound) > 0 {
+		return fmt.Errorf("bound should be in [0, %s]", dlog.MaxBound)
+	}
+	// m² > bound, so that the giant steps i * m, i < m cover [0, bound]
+	m := new(big.Int).Sqrt(bound)
+	m.Add(m, big.NewInt(1))
+	n := uint64(1)
+	for n < 2*m.Uint64() {
+		n <<= 1
+	}
+
+	var buf bytes.Buffer
+	buf.Write(sharedTableMagic)
+	for _, x := range []*big.Int{P, g} {
+		b := x.Bytes()
+		if len(b) > sharedTableMaxIntLen {
+			return fmt.Errorf("group element should have at most %d bytes", sharedTableMaxIntLen)
+		}
+		binary.Write(&buf, binary.BigEndian, uint32(len(b)))
+		buf.Write(b)
+	}
+	binary.Write(&buf, binary.BigEndian, m.Uint64())
+	binary.Write(&buf, binary.BigEndian, n)
+
+	slots := make([]byte, n*sharedTableSlotSize)
+	x := big.NewInt(1)
+	for j := uint64(0); j < m.Uint64(); j++ {
+		key := sharedTableHash(x)
+		for i := key & (n - 1); ; i = (i + 1) & (n - 1) {
+			slot := slots[i*sharedTableSlotSize:]
+			if binary.BigEndian.Uint64(slot[8:]) == 0 {
+				binary.BigEndian.PutUint64(slot, key)
+				binary.BigEndian.PutUint64(slot[8:], j+1)
+				break
+			}
+		}
+		x.Mod(x.Mul(x, g), P)
+	}
+	buf.Write(slots)
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// OpenSharedTable memory-maps the table written by WriteSharedTable
+// to the file at path read-only. On platforms without memory mapping
+// the file is read into memory instead. The table should be closed
+// with Close when it is not needed anymore.
+// It returns an error if the file could not be opened or is not a
+// valid table.
+func OpenSharedTable(path string) (*SharedTable, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseSharedTable(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("invalid shared table %s: %v", path, err)
+	}
+	t.unmap = unmap
+
+	return t, nil
+}
+
+// parseSharedTable checks the header of the table in data and
+// returns the table reading the slots from data.
+func parseSharedTable(data []byte) (*SharedTable, error) {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(sharedTableMagic))
+	if _, err := r.Read(magic); err != nil || !bytes.Equal(magic, sharedTableMagic) {
+		return nil, fmt.Errorf("missing magic")
+	}
+	ints := make([]*big.Int, 2)
+	for i := range ints {
+		var l uint32
+		if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+			return nil, err
+		}
+		if l == 0 || l > sharedTableMaxIntLen || int(l) > r.Len() {
+			return nil, fmt.Errorf("invalid length of group element")
+		}
+		b := make([]byte, l)
+		r.Read(b)
+		ints[i] = new(big.Int).SetBytes(b)
+	}
+	var m, n uint64
+	if err := binary.Read(r, binary.BigEndian, &m); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if m == 0 || m > maxSharedTableSteps {
+		return nil, fmt.Errorf("number of baby steps should be in [1, %d]", maxSharedTableSteps)
+	}
+	if n < m || n&(n-1) != 0 || n > 2*maxSharedTableSteps {
+		return nil, fmt.Errorf("invalid number of slots")
+	}
+	if uint64(r.Len()) != n*sharedTableSlotSize {
+		return nil, fmt.Errorf("size of the slots does not match the header")
+	}
+	offset := len(data) - r.Len()
+
+	return &SharedTable{
+		data:  data,
+		p:     ints[0],
+		g:     ints[1],
+		m:     m,
+		slots: data[offset:],
+		mask:  n - 1,
+	}, nil
+}
+
+// sharedTableHash returns the fnv-1a hash of the bytes of x.
+func sharedTableHash(x *big.Int) uint64 {
+	h := fnv.New64a()
+	h.Write(x.Bytes())
+	return h.Sum64()
+}
+
+// lookup calls f with every j for which g^j may equal y, until f
+// returns true. The candidates need to be verified by f, since only
+// the hashes of the baby steps are stored.
+func (t *SharedTable) lookup(y *big.Int, f func(j uint64) bool) {
+	key := sharedTableHash(y)
+	for i, k := key&t.mask, uint64(0); k <= t.mask; i, k = (i+1)&t.mask, k+1 {
+		slot := t.slots[i*sharedTableSlotSize:]
+		j := binary.BigEndian.Uint64(slot[8:])
+		if j == 0 {
+			return
+		}
+		if binary.BigEndian.Uint64(slot) == key && j <= t.m && f(j-1) {
+			return
+		}
+	}
+}
+
+// Solve returns x with |x| <= bound such that g^x = h (mod P) using
+// the baby-step giant-step method with the baby steps of the table.
+// It returns an error if g and P differ from the generator and the
+// group of the table, if bound is not smaller than m², or if there is
+// no such x.
+func (t *SharedTable) Solve(h, g, P, Q, bound *big.Int) (*big.Int, error) {
+	if t.slots == nil {
+		return nil, fmt.Errorf("shared table is closed")
+	}
+	if g.Cmp(t.g) != 0 || P.Cmp(t.p) != 0 {
+		return nil, fmt.Errorf("shared table was computed for a different generator or group")
+	}
+	m := new(big.Int).SetUint64(t.m)
+	if bound.Cmp(new(big.Int).Mul(m, m)) >= 0 {
+		return nil, fmt.Errorf("bound should be smaller than %d² for the shared table", t.m)
+	}
+
+	// g^-m
+	giantStep := new(big.Int).ModInverse(g, P)
+	giantStep.Exp(giantStep, m, P)
+	// candidates for x and -x
+	y := new(big.Int).Set(h)
+	yNeg := new(big.Int).ModInverse(h, P)
+	if yNeg == nil {
+		return nil, fmt.Errorf("element is not invertible")
+	}
+
+	var res *big.Int
+	for i := uint64(0); i < t.m && res == nil; i++ {
+		for _, c := range []struct {
+			y    *big.Int
+			sign int64
+		}{{y, 1}, {yNeg, -1}} {
+			t.lookup(c.y, func(j uint64) bool {
+				x := new(big.Int).SetUint64(i)
+				x.Mul(x, m)
+				x.Add(x, new(big.Int).SetUint64(j))
+				if x.Cmp(bound) > 0 {
+					return false
+				}
+				// the hashes of g^j and y may collide
+				if new(big.Int).Exp(g, new(big.Int).SetUint64(j), P).Cmp(c.y) != 0 {
+					return false
+				}
+				res = x.Mul(x, big.NewInt(c.sign))
+				return true
+			})
+			if res != nil {
+				break
+			}
+		}
+		y.Mod(y.Mul(y, giantStep), P)
+		yNeg.Mod(yNeg.Mul(yNeg, giantStep), P)
+	}
+	if res == nil {
+		return nil, fmt.Errorf("failed to find the discrete logarithm within bound " + bound.String())
+	}
+
+	return res, nil
+}
+
+// Close unmaps the file of the table. The table cannot be used
+// afterwards.
+func (t *SharedTable) Close() error {
+	if t.slots == nil {
+		return nil
+	}
+	t.slots = nil
+	t.data = nil
+
+	return t.unmap()
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog_test
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedTable(t *testing.T) {
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "gofe-dlog")
+	if err != nil {
+		t.Fatalf("Error during creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "table")
+
+	bound := big.NewInt(10000)
+	if err := dlog.WriteSharedTable(path, key.G, key.P, bound); err != nil {
+		t.Fatalf("Error during writing the table: %v", err)
+	}
+	table, err := dlog.OpenSharedTable(path)
+	if err != nil {
+		t.Fatalf("Error during opening the table: %v", err)
+	}
+	defer table.Close()
+
+	var solver dlog.Solver = table
+	for _, x := range []int64{-10000, -101, -1, 0, 1, 100, 9999, 10000} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := solver.Solve(h, key.G, key.P, key.Q, bound)
+		if err != nil {
+			t.Fatalf("Error during computing the discrete logarithm of %d: %v", x, err)
+		}
+		assert.Equal(t, x, res.Int64())
+	}
+
+	h := internal.ModExp(key.G, big.NewInt(10001), key.P)
+	_, err = solver.Solve(h, key.G, key.P, key.Q, bound)
+	assert.Error(t, err, "solution beyond the bound should not be found")
+	_, err = solver.Solve(h, key.G, key.P, key.Q, big.NewInt(1000000))
+	assert.Error(t, err, "bound beyond the table should be rejected")
+	g2 := new(big.Int).Exp(key.G, big.NewInt(2), key.P)
+	_, err = solver.Solve(h, g2, key.P, key.Q, bound)
+	assert.Error(t, err, "different generator should be rejected")
+
+	assert.NoError(t, table.Close())
+	_, err = table.Solve(h, key.G, key.P, key.Q, bound)
+	assert.Error(t, err, "closed table should not be used")
+}
+
+func TestOpenSharedTable_Invalid(t *testing.T) {
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "gofe-dlog")
+	if err != nil {
+		t.Fatalf("Error during creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "table")
+
+	if err := dlog.WriteSharedTable(path, key.G, key.P, big.NewInt(100)); err != nil {
+		t.Fatalf("Error during writing the table: %v", err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error during reading the table: %v", err)
+	}
+
+	truncated := filepath.Join(dir, "truncated")
+	if err := ioutil.WriteFile(truncated, b[:len(b)-1], 0600); err != nil {
+		t.Fatalf("Error during writing the file: %v", err)
+	}
+	_, err = dlog.OpenSharedTable(truncated)
+	assert.Error(t, err, "truncated table should be rejected")
+
+	// a corrupted slot never gives a wrong solution
+	corrupted := filepath.Join(dir, "corrupted")
+	for i := len(b) - 16*16; i < len(b); i += 16 {
+		b[i+15] ^= 1
+	}
+	if err := ioutil.WriteFile(corrupted, b, 0600); err != nil {
+		t.Fatalf("Error during writing the file: %v", err)
+	}
+	table, err := dlog.OpenSharedTable(corrupted)
+	if err != nil {
+		t.Fatalf("Error during opening the table: %v", err)
+	}
+	defer table.Close()
+	for x := int64(-100); x <= 100; x++ {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := table.Solve(h, key.G, key.P, key.Q, big.NewInt(100))
+		if err == nil {
+			assert.Equal(t, x, res.Int64())
+		}
+	}
+
+	_, err = dlog.OpenSharedTable(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	assert.Error(t, dlog.WriteSharedTable(path, key.G, key.P, new(big.Int).Lsh(big.NewInt(1), 49)))
+}
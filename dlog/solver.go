@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// Solver computes discrete logarithms in the Z_P group. It allows
+// the schemes to delegate the computation, e.g. to a hardware
+// accelerated service.
+type Solver interface {
+	// Solve returns x with |x| <= bound such that g^x = h (mod P),
+	// where Q is the order of g, or an error if there is no such x.
+	Solve(h, g, P, Q, bound *big.Int) (*big.Int, error)
+}
+
+// BabyStepGiantStepSolver is the default Solver, computing discrete
+// logarithms with the baby-step giant-step method. If Tables is set,
+// the baby steps are cached in it and reused across calls.
+type BabyStepGiantStepSolver struct {
+	Tables *TableCache
+}
+
+// Solve returns x with |x| <= bound such that g^x = h (mod P) using
+// the baby-step giant-step method.
+func (s BabyStepGiantStepSolver) Solve(h, g, P, Q, bound *big.Int) (*big.Int, error) {
+	calc, err := dlog.NewCalc().InZp(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if s.Tables != nil {
+		calc = calc.WithTableCache(s.Tables)
+	}
+
+	return calc.WithNeg().WithBound(bound).BabyStepGiantStep(h, g)
+}
+
+// KangarooSolver is a Solver computing discrete logarithms with
+// Pollard's kangaroo (lambda) method, which needs memory independent
+// of the bound, unlike BabyStepGiantStepSolver, whose table of baby
+// steps takes memory proportional to sqrt(bound), at the cost of more
+// time. Bounds should be smaller than 2^48.
+//
+// If TableBound is set, bounds up to TableBound are solved with
+// BabyStepGiantStepSolver instead, so that the method is picked based
+// on the range of the expected result: the faster one while its table
+// is small enough, the kangaroo method beyond.
+type KangarooSolver struct {
+	TableBound *big.Int
+}
+
+// Solve returns x with |x| <= bound such that g^x = h (mod P) using
+// the kangaroo method, or the baby-step giant-step method if bound
+// does not exceed s.TableBound.
+func (s KangarooSolver) Solve(h, g, P, Q, bound *big.Int) (*big.Int, error) {
+	if s.TableBound != nil && bound.Cmp(s.TableBound) <= 0 {
+		return BabyStepGiantStepSolver{}.Solve(h, g, P, Q, bound)
+	}
+	calc, err := dlog.NewCalc().InZp(P, Q)
+	if err != nil {
+		return nil, err
+	}
+
+	return calc.WithNeg().WithBound(bound).WithKangaroo().Solve(h, g)
+}
+
+// SolveWindowed computes the discrete logarithm with solver s, also
+// when the solution lies beyond the bound. It searches successive
+// windows [center - bound, center + bound] for centers 0, w, -w, 2w,
+// -2w, ..., where w = 2 * bound + 1, until the solution is found. Each
+// window is searched with a single call of s.Solve, thus the time
+// needed grows linearly with the number of windows searched, and an
+// error of s.Solve is taken as the solution not being in the window.
+//
+// The search stops with an error once the windows exceed max in
+// absolute value, so max should be set to the largest absolute
+// value of the solution that is worth waiting for.
+func SolveWindowed(s Solver, h, g, P, Q, bound, max *big.Int) (*big.Int, error) {
+	width := new(big.Int).Lsh(bound, 1)
+	width.Add(width, big.NewInt(1))
+	// g^w and g^-w shift the windows
+	gW := new(big.Int).Exp(g, width, P)
+	gWInv := new(big.Int).ModInverse(gW, P)
+
+	// h * g^-center for the positive and the negative center
+	hPos := new(big.Int).Set(h)
+	hNeg := new(big.Int).Set(h)
+	center := big.NewInt(0)
+	lowest := new(big.Int)
+	for {
+		lowest.Sub(center, bound)
+		if lowest.Cmp(max) > 0 {
+			break
+		}
+
+		candidates := []*big.Int{hPos}
+		centers := []*big.Int{center}
+		if center.Sign() > 0 {
+			candidates = append(candidates, hNeg)
+			centers = append(centers, new(big.Int).Neg(center))
+		}
+		for i, hc := range candidates {
+			res, err := s.Solve(hc, g, P, Q, bound)
+			if err != nil {
+				continue
+			}
+			res.Add(res, centers[i])
+			if new(big.Int).Abs(res).Cmp(max) > 0 {
+				continue
+			}
+			return res, nil
+		}
+
+		center.Add(center, width)
+		hPos.Mod(hPos.Mul(hPos, gWInv), P)
+		hNeg.Mod(hNeg.Mul(hNeg, gW), P)
+	}
+
+	return nil, fmt.Errorf("failed to find the discrete logarithm within maximal absolute value " + max.String())
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBabyStepGiantStepSolver(t *testing.T) {
+	key, err := keygen.NewElGamal(20)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	var solver dlog.Solver = dlog.BabyStepGiantStepSolver{}
+	for _, x := range []int64{-1000, -1, 0, 7, 1000} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := solver.Solve(h, key.G, key.P, key.Q, big.NewInt(1000))
+		if err != nil {
+			t.Fatalf("Error in solver: %v", err)
+		}
+		assert.Equal(t, 0, res.Cmp(big.NewInt(x)), "solver result is wrong")
+	}
+}
+
+func TestKangarooSolver(t *testing.T) {
+	key, err := keygen.NewElGamal(20)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	solvers := []dlog.KangarooSolver{
+		{},
+		{TableBound: big.NewInt(100)},
+		{TableBound: big.NewInt(10000)},
+	}
+	for _, solver := range solvers {
+		for _, x := range []int64{-1000, -1, 0, 7, 1000} {
+			h := internal.ModExp(key.G, big.NewInt(x), key.P)
+			res, err := solver.Solve(h, key.G, key.P, key.Q, big.NewInt(1000))
+			if err != nil {
+				t.Fatalf("Error in solver: %v", err)
+			}
+			assert.Equal(t, 0, res.Cmp(big.NewInt(x)), "solver result is wrong")
+		}
+	}
+
+	// the kangaroo method does not find values outside of the bound
+	h := internal.ModExp(key.G, big.NewInt(1001), key.P)
+	_, err = solvers[1].Solve(h, key.G, key.P, key.Q, big.NewInt(1000))
+	assert.Error(t, err)
+}
+
+func TestBabyStepGiantStepSolver_Tables(t *testing.T) {
+	key, err := keygen.NewElGamal(20)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	solver := dlog.BabyStepGiantStepSolver{Tables: dlog.NewTableCache()}
+	bound := big.NewInt(10000)
+
+	n := 16
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			x := big.NewInt(int64(i*500 - 4000))
+			h := internal.ModExp(key.G, x, key.P)
+			res, err := solver.Solve(h, key.G, key.P, key.Q, bound)
+			if err == nil && res.Cmp(x) != 0 {
+				err = assert.AnError
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	var stats dlog.TableStats = solver.Tables.Stats()
+	assert.Equal(t, uint64(1), stats.Builds)
+	assert.Equal(t, uint64(n-1), stats.Reuses)
+}
+
+func TestSolveWindowed(t *testing.T) {
+	key, err := keygen.NewElGamal(128)
+	if err != nil {
+		t.Fatalf("Error in ElGamal key generation: %v", err)
+	}
+
+	solver := dlog.BabyStepGiantStepSolver{}
+	bound := big.NewInt(1000)
+	max := big.NewInt(100000)
+
+	for _, xCheck := range []*big.Int{big.NewInt(17), big.NewInt(-999), big.NewInt(54321),
+		big.NewInt(-54321), big.NewInt(100000), big.NewInt(-100000)} {
+		h := internal.ModExp(key.G, xCheck, key.P)
+		x, err := dlog.SolveWindowed(solver, h, key.G, key.P, key.Q, bound, max)
+		if err != nil {
+			t.Fatalf("Error in windowed search: %v", err)
+		}
+		assert.Equal(t, 0, xCheck.Cmp(x), "SolveWindowed result is wrong")
+	}
+
+	h := internal.ModExp(key.G, big.NewInt(-150000), key.P)
+	_, err = dlog.SolveWindowed(solver, h, key.G, key.P, key.Q, bound, max)
+	assert.Error(t, err, "solution beyond max should not be found")
+}
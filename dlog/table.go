@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// TableCache holds baby-step tables of BabyStepGiantStepSolver, so
+// that they are built only once and reused across computations of
+// discrete logarithms with the same group and generator. A table built
+// for a smaller bound is extended when a larger bound is requested. It
+// is safe for concurrent use.
+type TableCache = dlog.TableCache
+
+// TableStats holds the counters of a TableCache: the number of tables
+// built, the number of requests served by an already built table, the
+// number of tables extended to a larger bound, and the number of
+// lookups in the tables.
+type TableStats = dlog.TableStats
+
+// NewTableCache returns an empty TableCache.
+func NewTableCache() *TableCache {
+	return dlog.NewTableCache()
+}
+
+// Table is a table of baby steps of a generator g in Z_P, allowing
+// the computation of discrete logarithms x with |x| <= Bound() without
+// recomputing the baby steps. It is a Solver for g and P, and can be
+// extended to a larger bound with Extend, adding only the missing baby
+// steps. It is safe for concurrent use.
+type Table = dlog.Table
+
+// NewTable computes the table of baby steps of generator g in Z_P
+// needed to compute discrete logarithms x with |x| <= bound. It
+// returns an error if bound is not positive or is not smaller than
+// 2^48.
+func NewTable(g, P, bound *big.Int) (*Table, error) {
+	return dlog.NewTable(P, g, bound)
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable(t *testing.T) {
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	table, err := dlog.NewTable(key.G, key.P, big.NewInt(1<<8))
+	if err != nil {
+		t.Fatalf("Error during table creation: %v", err)
+	}
+	var solver dlog.Solver = table
+	h := internal.ModExp(key.G, big.NewInt(1<<9), key.P)
+	_, err = solver.Solve(h, key.G, key.P, key.Q, big.NewInt(1<<9))
+	assert.Error(t, err, "bound beyond the table should be rejected")
+
+	if err := table.Extend(big.NewInt(1 << 9)); err != nil {
+		t.Fatalf("Error during table extension: %v", err)
+	}
+	res, err := solver.Solve(h, key.G, key.P, key.Q, big.NewInt(1<<9))
+	if err != nil {
+		t.Fatalf("Error during computing the discrete logarithm: %v", err)
+	}
+	assert.Equal(t, int64(1<<9), res.Int64())
+
+	_, err = dlog.NewTable(key.G, key.P, big.NewInt(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fe
+
+import "errors"
+
+// ErrBoundTooLarge is returned (wrapped with the actual values) by
+// scheme constructors when the bound on the inputs is too large for
+// the order of the group, so that inner products could not be
+// recovered. Callers can detect it with errors.Is and, for example,
+// retry with a larger modulus.
+var ErrBoundTooLarge = errors.New("bound is too large for the group order")
+
+// ErrClosed is returned by the methods of scheme instances that were
+// closed with Close.
+var ErrClosed = errors.New("scheme instance is closed")
+
+// ErrWeakParams is returned (wrapped with the estimated strength) when
+// the parameters of a scheme provide less than 112 bits of security,
+// the minimum recommended by NIST SP 800-57.
+var ErrWeakParams = errors.New("parameters provide less than 112 bits of security")
+
+// ErrOverflow is returned (wrapped with the actual value) when a
+// result does not fit into the requested fixed-size integer type.
+var ErrOverflow = errors.New("result overflows the integer type")
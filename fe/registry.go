@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fe
+
+import (
+	"sort"
+	"sync"
+)
+
+// Assumptions on which the schemes are based.
+const (
+	DDH     = "DDH"
+	LWE     = "LWE"
+	RingLWE = "ring-LWE"
+	DCR     = "DCR"
+	SXDH    = "SXDH"
+)
+
+// SchemeInfo describes a functional encryption scheme.
+type SchemeInfo struct {
+	// Name of the scheme, e.g. "simple.DDH".
+	Name string
+	// The hardness assumption the scheme is based on.
+	Assumption string
+	// Whether the scheme is fully (adaptively) secure as opposed
+	// to selectively secure.
+	FullySecure bool
+	// Modulus lengths for which precomputed parameters are
+	// available, nil if the scheme has none.
+	ModulusLengths []int
+}
+
+var (
+	mu      sync.RWMutex
+	schemes = make(map[string]SchemeInfo)
+)
+
+// Register declares the metadata of a scheme. It is meant to be
+// called by scheme packages when they are initialized. It panics
+// if a scheme with the same name is already registered.
+func Register(info SchemeInfo) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := schemes[info.Name]; ok {
+		panic("fe: scheme " + info.Name + " registered twice")
+	}
+	schemes[info.Name] = info
+}
+
+// List returns the descriptions of all the registered schemes,
+// sorted by name.
+func List() []SchemeInfo {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]SchemeInfo, 0, len(schemes))
+	for _, info := range schemes {
+		info.ModulusLengths = append([]int(nil), info.ModulusLengths...)
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fe_test
+
+import (
+	"testing"
+
+	"github.com/fentec-project/gofe/fe"
+	_ "github.com/fentec-project/gofe/innerprod/fullysec"
+	_ "github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	list := fe.List()
+
+	infos := make(map[string]fe.SchemeInfo)
+	for i, info := range list {
+		infos[info.Name] = info
+		if i > 0 {
+			assert.True(t, list[i-1].Name < info.Name, "schemes should be sorted by name")
+		}
+	}
+
+	ddh, ok := infos["simple.DDH"]
+	assert.True(t, ok)
+	assert.Equal(t, fe.DDH, ddh.Assumption)
+	assert.False(t, ddh.FullySecure)
+	assert.Contains(t, ddh.ModulusLengths, 2048)
+
+	damgard, ok := infos["fullysec.Damgard"]
+	assert.True(t, ok)
+	assert.True(t, damgard.FullySecure)
+
+	// modifying the returned list should not affect the registry
+	ddh.ModulusLengths[0] = 0
+	for _, info := range fe.List() {
+		if info.Name == "simple.DDH" {
+			assert.NotEqual(t, 0, info.ModulusLengths[0])
+		}
+	}
+
+	assert.Panics(t, func() { fe.Register(fe.SchemeInfo{Name: "simple.DDH"}) })
+}
@@ -25,4 +25,9 @@
 // well as multi input schemes. Construction of all multi input
 // schemes is based on the work of Abdalla et. al (see paper:
 // https://eprint.iacr.org/2017/972.pdf)
+//
+// For callers that do not need a particular scheme, NewAuto constructs
+// a DDH based scheme of the desired security level behind the common
+// Scheme interface. KeyManager keeps several such schemes, with their
+// master keys and derived keys, under names.
 package innerprod
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// DamgardCiphertext is a ciphertext of the Damgard scheme with named
+// components: C = g^r, DD = h^r and Components holding
+// e_i = h_i^r * g^x_i for the coordinates of x. Encrypt and Decrypt
+// use the legacy form of a single vector (C, DD, e_1, ..., e_l), to
+// which it is converted with Vector and from which with
+// NewDamgardCiphertext.
+type DamgardCiphertext struct {
+	C          *big.Int
+	DD         *big.Int
+	Components data.Vector
+}
+
+// NewDamgardCiphertext returns the ciphertext in the legacy vector
+// form cipher (C, DD, e_1, ..., e_l) with named components. The
+// components are not copied. It returns an error if cipher holds
+// fewer than 3 components.
+func NewDamgardCiphertext(cipher data.Vector) (*DamgardCiphertext, error) {
+	if len(cipher) < 3 {
+		return nil, internal.ErrMalformedCipher
+	}
+
+	return &DamgardCiphertext{
+		C:          cipher[0],
+		DD:         cipher[1],
+		Components: cipher[2:],
+	}, nil
+}
+
+// Vector returns the ciphertext in the legacy vector form
+// (C, DD, e_1, ..., e_l), as returned by Encrypt. The components are
+// not copied.
+func (c *DamgardCiphertext) Vector() data.Vector {
+	return append(data.Vector{c.C, c.DD}, c.Components...)
+}
+
+// EncryptCiphertext works like Encrypt, but returns the ciphertext
+// with named components.
+func (d *Damgard) EncryptCiphertext(x, masterPubKey data.Vector) (*DamgardCiphertext, error) {
+	cipher, err := d.Encrypt(x, masterPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDamgardCiphertext(cipher)
+}
+
+// DecryptCiphertext works like Decrypt, but accepts the ciphertext
+// with named components.
+func (d *Damgard) DecryptCiphertext(cipher *DamgardCiphertext, key *DamgardDerivedKey, y data.Vector) (*big.Int, error) {
+	if cipher == nil || cipher.C == nil || cipher.DD == nil {
+		return nil, internal.ErrMalformedCipher
+	}
+
+	return d.Decrypt(cipher.Vector(), key, y)
+}
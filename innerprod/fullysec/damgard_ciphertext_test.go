@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardCiphertext(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	y := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(6)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	cipher, err := damgard.EncryptCiphertext(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, 2, len(cipher.Components))
+	xy, err := damgard.DecryptCiphertext(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-39), xy.Int64(), "obtained incorrect inner product")
+
+	// conversion to and from the legacy form
+	v := cipher.Vector()
+	assert.Equal(t, 4, len(v))
+	assert.Equal(t, cipher.C, v[0])
+	assert.Equal(t, cipher.DD, v[1])
+	xy, err = damgard.Decrypt(v, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-39), xy.Int64(), "obtained incorrect inner product")
+	back, err := fullysec.NewDamgardCiphertext(v)
+	if err != nil {
+		t.Fatalf("Error during conversion: %v", err)
+	}
+	assert.Equal(t, cipher, back)
+
+	_, err = fullysec.NewDamgardCiphertext(v[:2])
+	assert.Error(t, err, "too short ciphertext should be rejected")
+	_, err = damgard.DecryptCiphertext(&fullysec.DamgardCiphertext{Components: cipher.Components}, key, y)
+	assert.Error(t, err, "ciphertext without C and DD should be rejected")
+	cipher.Components = cipher.Components[:1]
+	_, err = damgard.DecryptCiphertext(cipher, key, y)
+	assert.Error(t, err, "ciphertext of wrong length should be rejected")
+}
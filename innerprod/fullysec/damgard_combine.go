@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// DecryptAndCombine decrypts each of the ciphertexts ciphers[i] with
+// the functional encryption key keys[i] derived for ys[i], and returns
+// the result of combiner applied to the inner products, in the order
+// of the ciphertexts.
+//
+// Since all the inner products are searched within the same bound, the
+// baby-step table of the discrete logarithm computation is built once
+// and shared by the decryptions, unless a solver or a table cache was
+// already set on the scheme, in which case it is used instead. It
+// returns an error if the slices differ in length or are empty, if
+// combiner is nil, or if any of the decryptions failed.
+func (d *Damgard) DecryptAndCombine(ciphers []data.Vector, keys []*DamgardDerivedKey, ys []data.Vector,
+	combiner func([]*big.Int) *big.Int) (*big.Int, error) {
+	if len(ciphers) == 0 || len(keys) != len(ciphers) || len(ys) != len(ciphers) {
+		return nil, fmt.Errorf("ciphertexts, keys and vectors y should be non-empty and of the same length")
+	}
+	if combiner == nil {
+		return nil, fmt.Errorf("combiner should not be nil")
+	}
+
+	dec := d
+	if d.solver == nil {
+		dec = d.WithTableCache()
+	}
+	res := make([]*big.Int, len(ciphers))
+	for i, cipher := range ciphers {
+		xy, err := dec.Decrypt(cipher, keys[i], ys[i])
+		if err != nil {
+			return nil, fmt.Errorf("decryption of ciphertext %d failed: %w", i, err)
+		}
+		res[i] = xy
+	}
+
+	return combiner(res), nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardDecryptAndCombine(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	xs := []data.Vector{
+		data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)}),
+		data.NewVector([]*big.Int{big.NewInt(10), big.NewInt(20)}),
+	}
+	ys := []data.Vector{
+		data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(6)}),
+		data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)}),
+	}
+	ciphers := make([]data.Vector, len(xs))
+	keys := make([]*fullysec.DamgardDerivedKey, len(xs))
+	for i := range xs {
+		if ciphers[i], err = damgard.Encrypt(xs[i], masterPubKey); err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		if keys[i], err = damgard.DeriveKey(masterSecKey, ys[i]); err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+	}
+
+	product := func(xys []*big.Int) *big.Int {
+		res := big.NewInt(1)
+		for _, xy := range xys {
+			res.Mul(res, xy)
+		}
+		return res
+	}
+	// -39 * 50
+	res, err := damgard.DecryptAndCombine(ciphers, keys, ys, product)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-1950), res.Int64())
+	assert.Equal(t, dlog.TableStats{}, damgard.TableStats(), "scheme instance should not be modified")
+
+	_, err = damgard.DecryptAndCombine(ciphers, keys[:1], ys, product)
+	assert.Error(t, err, "slices of different lengths should be rejected")
+	_, err = damgard.DecryptAndCombine(ciphers, keys, ys, nil)
+	assert.Error(t, err, "missing combiner should be rejected")
+	_, err = damgard.DecryptAndCombine(ciphers, []*fullysec.DamgardDerivedKey{keys[1], keys[0]}, ys, product)
+	assert.Error(t, err, "wrong keys should make decryption fail")
+}
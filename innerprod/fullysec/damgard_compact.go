@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal"
+)
+
+// CompactKeySize returns the size in bytes of a derived key encoded
+// with MarshalCompactKey, i.e. twice the byte length of the group
+// order Q.
+//
+// Note that a derived key cannot be reduced to a single scalar: Key1
+// and Key2 are inner products of y with the independent secret vectors
+// s and t, and the security of the scheme relies on Key2 not being
+// computable from Key1 and public data. Thus the compact encoding only
+// avoids the overhead of a general purpose serialization.
+func (d *Damgard) CompactKeySize() int {
+	return 2 * d.scalarSize()
+}
+
+// MarshalCompactKey encodes the derived key as Key1 followed by Key2,
+// each as a big-endian integer padded to the byte length of Q.
+// It returns an error if the scalars are not in [0, Q), as produced by
+// DeriveKey.
+func (d *Damgard) MarshalCompactKey(key *DamgardDerivedKey) ([]byte, error) {
+	if !d.isScalar(key.Key1) || !d.isScalar(key.Key2) {
+		return nil, internal.ErrMalformedDecKey
+	}
+
+	n := d.scalarSize()
+	b := make([]byte, 2*n)
+	key.Key1.FillBytes(b[:n])
+	key.Key2.FillBytes(b[n:])
+
+	return b, nil
+}
+
+// UnmarshalCompactKey decodes a derived key encoded with
+// MarshalCompactKey by a scheme instance with the same group order.
+// It returns an error if b is not of size CompactKeySize or the
+// decoded scalars are not in [0, Q).
+func (d *Damgard) UnmarshalCompactKey(b []byte) (*DamgardDerivedKey, error) {
+	if len(b) != d.CompactKeySize() {
+		return nil, fmt.Errorf("compact key should be of size %d, got %d", d.CompactKeySize(), len(b))
+	}
+
+	n := d.scalarSize()
+	key := &DamgardDerivedKey{
+		Key1: new(big.Int).SetBytes(b[:n]),
+		Key2: new(big.Int).SetBytes(b[n:]),
+	}
+	if !d.isScalar(key.Key1) || !d.isScalar(key.Key2) {
+		return nil, internal.ErrMalformedDecKey
+	}
+
+	return key, nil
+}
+
+// scalarSize returns the byte length of the group order Q.
+func (d *Damgard) scalarSize() int {
+	return (d.Params.Q.BitLen() + 7) / 8
+}
+
+// isScalar reports whether x is in [0, Q).
+func (d *Damgard) isScalar(x *big.Int) bool {
+	return x != nil && x.Sign() >= 0 && x.Cmp(d.Params.Q) < 0
+}
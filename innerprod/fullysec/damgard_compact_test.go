@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardCompactKey(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	y := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(6)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	b, err := damgard.MarshalCompactKey(key)
+	if err != nil {
+		t.Fatalf("Error during key encoding: %v", err)
+	}
+	assert.Equal(t, damgard.CompactKeySize(), len(b))
+	assert.Equal(t, 2*((damgard.Params.Q.BitLen()+7)/8), len(b))
+
+	decoded, err := damgard.UnmarshalCompactKey(b)
+	if err != nil {
+		t.Fatalf("Error during key decoding: %v", err)
+	}
+	assert.Equal(t, key.Fingerprint(), decoded.Fingerprint())
+	xy, err := damgard.Decrypt(cipher, decoded, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-39)), "obtained incorrect inner product")
+
+	// small scalars are padded to the fixed width
+	small := &fullysec.DamgardDerivedKey{Key1: big.NewInt(0), Key2: big.NewInt(1)}
+	b, err = damgard.MarshalCompactKey(small)
+	assert.NoError(t, err)
+	assert.Equal(t, damgard.CompactKeySize(), len(b))
+
+	_, err = damgard.MarshalCompactKey(&fullysec.DamgardDerivedKey{Key1: damgard.Params.Q, Key2: big.NewInt(1)})
+	assert.Error(t, err, "scalar out of range should be rejected")
+	_, err = damgard.UnmarshalCompactKey(b[1:])
+	assert.Error(t, err, "wrong size should be rejected")
+	invalid := make([]byte, len(b))
+	for i := range invalid {
+		invalid[i] = 0xff
+	}
+	_, err = damgard.UnmarshalCompactKey(invalid)
+	assert.Error(t, err, "scalar out of range should be rejected")
+}
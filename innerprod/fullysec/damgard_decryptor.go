@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+)
+
+// DamgardDecryptor decrypts ciphertexts of a scheme instance with the
+// baby-step table of the discrete logarithm computed once, when the
+// decryptor is created, and reused by every decryption. It suits
+// decrypting many ciphertexts with the same parameters, where
+// Decrypt would otherwise rebuild the table each time. It is safe for
+// concurrent use.
+type DamgardDecryptor struct {
+	damgard *Damgard
+}
+
+// NewDecryptor returns a decryptor of the scheme instance, computing
+// the baby-step table for the whole range of inner products, which
+// takes time and memory proportional to sqrt(l * bound²). The
+// decryptor uses its own table cache, replacing any solver set with
+// WithSolver. It returns an error if the range is too large for a
+// table.
+func (d *Damgard) NewDecryptor() (*DamgardDecryptor, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	c := d.WithTableCache()
+	if err := c.tables.Precompute(d.Params.P, d.Params.G, d.dlogBound()); err != nil {
+		return nil, err
+	}
+
+	return &DamgardDecryptor{damgard: c}, nil
+}
+
+// Decrypt works like Damgard.Decrypt, using the precomputed baby-step
+// table. A ciphertext that does not pass Damgard.CheckCiphertextShape is
+// rejected with its error before decryption.
+func (dec *DamgardDecryptor) Decrypt(cipher data.Vector, key *DamgardDerivedKey, y data.Vector) (*big.Int, error) {
+	if err := dec.damgard.CheckCiphertextShape(cipher); err != nil {
+		return nil, err
+	}
+
+	return dec.damgard.Decrypt(cipher, key, y)
+}
+
+// TableStats returns the number of builds, reuses and lookups of the
+// baby-step table of the decryptor. The table is built once, when the
+// decryptor is created.
+func (dec *DamgardDecryptor) TableStats() dlog.TableStats {
+	return dec.damgard.TableStats()
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardDecryptor(t *testing.T) {
+	l := 3
+	bound := big.NewInt(100)
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	dec, err := damgard.NewDecryptor()
+	if err != nil {
+		t.Fatalf("Error during decryptor creation: %v", err)
+	}
+	assert.Equal(t, uint64(1), dec.TableStats().Builds, "table should be built in advance")
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+	n := 5
+	for i := 0; i < n; i++ {
+		x, _ := data.NewRandomVector(l, sampler)
+		y, _ := data.NewRandomVector(l, sampler)
+		key, err := damgard.DeriveKey(masterSecKey, y)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		cipher, err := damgard.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		xy, err := dec.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		xyCheck, _ := x.Dot(y)
+		assert.Equal(t, 0, xy.Cmp(xyCheck), "obtained incorrect inner product")
+	}
+
+	stats := dec.TableStats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should not be rebuilt")
+	assert.Equal(t, uint64(n), stats.Reuses, "precomputed table should be reused")
+	assert.Equal(t, uint64(0), damgard.TableStats().Builds, "original instance should not cache tables")
+}
+
+func TestFullySec_DamgardDecryptorMalformedCipher(t *testing.T) {
+	l := 3
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	dec, err := damgard.NewDecryptor()
+	if err != nil {
+		t.Fatalf("Error during decryptor creation: %v", err)
+	}
+	y := data.NewConstantVector(l, big.NewInt(1))
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(data.NewConstantVector(l, big.NewInt(2)), masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// a zero in any component is rejected before the search
+	for i := range cipher {
+		malformed := append(data.Vector{}, cipher...)
+		malformed[i] = big.NewInt(0)
+		_, err := dec.Decrypt(malformed, key, y)
+		assert.True(t, errors.Is(err, internal.ErrMalformedCipher),
+			"zero component %d: expected malformed ciphertext, got %v", i, err)
+	}
+	_, err = dec.Decrypt(cipher[:l], key, y)
+	assert.True(t, errors.Is(err, internal.ErrMalformedCipher), "expected malformed ciphertext, got %v", err)
+}
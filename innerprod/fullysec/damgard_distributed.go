@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// DecryptToGroupElement performs the first part of decryption, i.e.
+// Decrypt without the computation of the discrete logarithm. It returns
+// h = G^<x,y>, the inner product of x and y in the exponent, for the
+// discrete logarithm to be computed elsewhere, e.g. split over workers
+// with dlog.SearchRange within [-SearchBound(), SearchBound()]. The
+// result is then obtained with FinishDecryptWithExponent.
+// It returns an error if the inputs are not valid.
+func (d *Damgard) DecryptToGroupElement(cipher data.Vector, key *DamgardDerivedKey, y data.Vector) (*big.Int, error) {
+	return d.innerProdElement(cipher, key, y)
+}
+
+// SearchBound returns the maximal absolute value l * bound² of the
+// inner product, i.e. of the discrete logarithm of the element
+// returned by DecryptToGroupElement.
+func (d *Damgard) SearchBound() *big.Int {
+	return d.dlogBound()
+}
+
+// FinishDecryptWithExponent completes a decryption started with
+// DecryptToGroupElement, given the exponent e found by an external
+// computation of the discrete logarithm of h. Since the exponent is
+// not trusted, it checks that |e| <= SearchBound() and G^e = h, and
+// returns e as the inner product. It returns an error otherwise.
+func (d *Damgard) FinishDecryptWithExponent(h, e *big.Int) (*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if new(big.Int).Abs(e).Cmp(d.dlogBound()) > 0 {
+		return nil, fmt.Errorf("exponent exceeds the bound of the inner product")
+	}
+	if internal.ModExp(d.Params.G, e, d.Params.P).Cmp(h) != 0 {
+		return nil, fmt.Errorf("exponent is not the discrete logarithm of the group element")
+	}
+
+	return new(big.Int).Set(e), nil
+}
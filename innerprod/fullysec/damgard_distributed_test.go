@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardFinishDecryptWithExponent(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	y := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(6)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	h, err := damgard.DecryptToGroupElement(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	bound := damgard.SearchBound()
+	assert.Equal(t, int64(2*100*100), bound.Int64())
+	var e *big.Int
+	for _, r := range [][2]*big.Int{{new(big.Int).Neg(bound), big.NewInt(-1)}, {big.NewInt(0), bound}} {
+		res, ok, err := dlog.SearchRange(h, damgard.Params.G, damgard.Params.P, r[0], r[1])
+		if err != nil {
+			t.Fatalf("Error during search: %v", err)
+		}
+		if ok {
+			e = res
+		}
+	}
+	if e == nil {
+		t.Fatalf("Discrete logarithm was not found")
+	}
+
+	xy, err := damgard.FinishDecryptWithExponent(h, e)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-39), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = damgard.FinishDecryptWithExponent(h, big.NewInt(39))
+	assert.Error(t, err, "wrong exponent should be rejected")
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// Fingerprint returns a short identifier of the derived key, e.g. to
+// be used as a key of a cache. Equal keys have equal fingerprints.
+// See FingerprintSalted for fingerprints that cannot be correlated
+// across tenants.
+func (k *DamgardDerivedKey) Fingerprint() string {
+	return k.FingerprintSalted(nil)
+}
+
+// FingerprintSalted returns a short identifier of the derived key,
+// computed with the salt mixed in before hashing. Using a distinct
+// salt per tenant makes the fingerprints of equal keys (e.g. keys for
+// the same vector y) differ across tenants, so that a shared cache
+// does not reveal that two tenants issued the same key.
+func (k *DamgardDerivedKey) FingerprintSalted(salt []byte) string {
+	return fingerprint(salt, data.NewVector([]*big.Int{k.Key1, k.Key2}))
+}
+
+// PubKeyFingerprint returns a short identifier of the master public
+// key. See PubKeyFingerprintSalted for fingerprints that cannot be
+// correlated across tenants.
+func PubKeyFingerprint(masterPubKey data.Vector) string {
+	return PubKeyFingerprintSalted(masterPubKey, nil)
+}
+
+// PubKeyFingerprintSalted returns a short identifier of the master
+// public key, computed with the salt mixed in before hashing.
+func PubKeyFingerprintSalted(masterPubKey data.Vector, salt []byte) string {
+	return fingerprint(salt, masterPubKey)
+}
+
+// fingerprint hashes the length prefixed salt followed by the
+// serialization of v, and returns the first 16 bytes of the hash
+// in hexadecimal.
+func fingerprint(salt []byte, v data.Vector) string {
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(salt)))
+	h.Write(buf[:])
+	h.Write(salt)
+	for _, c := range v {
+		b := c.Bytes()
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardFingerprint(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	y := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	sameKey, _ := damgard.DeriveKey(masterSecKey, y)
+	otherKey, _ := damgard.DeriveKey(masterSecKey, data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(1)}))
+
+	assert.Equal(t, key.Fingerprint(), sameKey.Fingerprint(), "equal keys should have equal fingerprints")
+	assert.NotEqual(t, key.Fingerprint(), otherKey.Fingerprint())
+	assert.Equal(t, key.Fingerprint(), key.FingerprintSalted(nil))
+
+	tenantA := key.FingerprintSalted([]byte("tenant A"))
+	tenantB := sameKey.FingerprintSalted([]byte("tenant B"))
+	assert.NotEqual(t, tenantA, tenantB, "salted fingerprints of equal keys should differ")
+	assert.NotEqual(t, key.Fingerprint(), tenantA)
+	assert.Equal(t, tenantA, sameKey.FingerprintSalted([]byte("tenant A")))
+
+	assert.Equal(t, fullysec.PubKeyFingerprint(masterPubKey), fullysec.PubKeyFingerprint(masterPubKey.Copy()))
+	assert.NotEqual(t, fullysec.PubKeyFingerprintSalted(masterPubKey, []byte("tenant A")),
+		fullysec.PubKeyFingerprintSalted(masterPubKey, []byte("tenant B")))
+}
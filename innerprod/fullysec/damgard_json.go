@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// damgardParamsJSON is the JSON form of DamgardParams, with all the
+// fields encoded as strings of decimal digits.
+type damgardParamsJSON struct {
+	L     string `json:"l"`
+	Bound string `json:"bound"`
+	G     string `json:"g"`
+	H     string `json:"h"`
+	P     string `json:"p"`
+	Q     string `json:"q"`
+}
+
+// MarshalJSON encodes the parameters as a JSON object with the fields
+// l, bound, g, h, p and q, all given as strings of decimal digits.
+func (params DamgardParams) MarshalJSON() ([]byte, error) {
+	if params.Bound == nil || params.G == nil || params.H == nil || params.P == nil || params.Q == nil {
+		return nil, fmt.Errorf("parameters should have Bound, G, H, P and Q")
+	}
+
+	return json.Marshal(damgardParamsJSON{
+		L:     strconv.Itoa(params.L),
+		Bound: params.Bound.String(),
+		G:     params.G.String(),
+		H:     params.H.String(),
+		P:     params.P.String(),
+		Q:     params.Q.String(),
+	})
+}
+
+// UnmarshalJSON decodes the parameters encoded by MarshalJSON. It
+// returns an error if a field is malformed or missing, if l is not
+// positive, if P is not greater than 2, if Q does not divide P - 1,
+// if G or H is not an element of order Q of Z_P*, or if H equals G.
+// Q is expected to be a prime, as with the parameters generated by
+// NewDamgard, so that the order of an element is Q exactly when it
+// is not 1 and its Q-th power is 1.
+func (params *DamgardParams) UnmarshalJSON(b []byte) error {
+	var j damgardParamsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	l, err := strconv.Atoi(j.L)
+	if err != nil || l < 1 {
+		return fmt.Errorf("l should be a positive decimal integer")
+	}
+
+	var ints [5]*big.Int
+	for i, s := range []string{j.Bound, j.G, j.H, j.P, j.Q} {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("%q is not a decimal integer", s)
+		}
+		ints[i] = n
+	}
+
+	bound, g, h, p, q := ints[0], ints[1], ints[2], ints[3], ints[4]
+	one := big.NewInt(1)
+	if p.Cmp(big.NewInt(2)) <= 0 {
+		return fmt.Errorf("p should be greater than 2")
+	}
+	if q.Sign() <= 0 || new(big.Int).Mod(new(big.Int).Sub(p, one), q).Sign() != 0 {
+		return fmt.Errorf("q should divide p - 1")
+	}
+	for _, e := range []struct {
+		name string
+		x    *big.Int
+	}{{"g", g}, {"h", h}} {
+		if e.x.Cmp(one) <= 0 || e.x.Cmp(p) >= 0 || new(big.Int).Exp(e.x, q, p).Cmp(one) != 0 {
+			return fmt.Errorf("%s should be an element of order q", e.name)
+		}
+	}
+	if g.Cmp(h) == 0 {
+		return fmt.Errorf("h should differ from g")
+	}
+
+	*params = DamgardParams{
+		L:     l,
+		Bound: bound,
+		G:     g,
+		H:     h,
+		P:     p,
+		Q:     q,
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardParamsJSON(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	b, err := json.Marshal(damgard.Params)
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	var params fullysec.DamgardParams
+	if err := json.Unmarshal(b, &params); err != nil {
+		t.Fatalf("Error during deserialization: %v", err)
+	}
+	assert.Equal(t, *damgard.Params, params)
+
+	// the decoded parameters give a working scheme
+	decoded := fullysec.NewDamgardFromParams(&params)
+	masterSecKey, masterPubKey, err := decoded.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := decoded.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := decoded.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := decoded.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+}
+
+func TestFullySec_DamgardParamsJSONInvalid(t *testing.T) {
+	var params fullysec.DamgardParams
+	assert.NoError(t, json.Unmarshal([]byte(`{"l":"2","bound":"1","g":"4","h":"9","p":"23","q":"11"}`), &params))
+
+	for _, s := range []string{
+		// h of order 2
+		`{"l":"2","bound":"1","g":"4","h":"22","p":"23","q":"11"}`,
+		// trivial h
+		`{"l":"2","bound":"1","g":"4","h":"1","p":"23","q":"11"}`,
+		// h equal to g
+		`{"l":"2","bound":"1","g":"4","h":"4","p":"23","q":"11"}`,
+		// g not in the group
+		`{"l":"2","bound":"1","g":"27","h":"9","p":"23","q":"11"}`,
+		`{"l":"2","bound":"1","g":"4","h":"9","p":"23","q":"7"}`,
+		`{"l":"0","bound":"1","g":"4","h":"9","p":"23","q":"11"}`,
+		`{"l":2,"bound":"1","g":"4","h":"9","p":"23","q":"11"}`,
+		`{"l":"2","g":"4","h":"9","p":"23","q":"11"}`,
+	} {
+		assert.Error(t, json.Unmarshal([]byte(s), &params), s)
+	}
+}
@@ -64,15 +64,12 @@ type DamgardMultiClient struct {
 // It returns an error in case the underlying Damgard scheme
 // instances could not be properly instantiated.
 func NewDamgardMulti(numClients, l, modulusLength int, bound *big.Int) (*DamgardMulti, error) {
-	bSquared := new(big.Int).Exp(bound, big.NewInt(2), nil)
-	prod := new(big.Int).Mul(big.NewInt(int64(l*numClients*2)), bSquared)
-
 	damgard, err := NewDamgard(l, modulusLength, bound)
 	if err != nil {
 		return nil, err
 	}
-	if prod.Cmp(damgard.Params.Q) > 0 {
-		return nil, fmt.Errorf("2 * l * numClients * bound^2 should be smaller than group order")
+	if err := internal.CheckOrderBound(l*numClients, bound, bound, damgard.Params.Q); err != nil {
+		return nil, err
 	}
 	// the bound of the underlying Damgard scheme is set to
 	// the maximum value since the scheme will be used to encrypt
@@ -100,15 +97,12 @@ func NewDamgardMulti(numClients, l, modulusLength int, bound *big.Int) (*Damgard
 // It returns an error in case the underlying Damgard scheme
 // instances could not be properly instantiated.
 func NewDamgardMultiPrecomp(numClients, l, modulusLength int, bound *big.Int) (*DamgardMulti, error) {
-	bSquared := new(big.Int).Exp(bound, big.NewInt(2), nil)
-	prod := new(big.Int).Mul(big.NewInt(int64(l*numClients*2)), bSquared)
-
 	damgard, err := NewDamgardPrecomp(l, modulusLength, bound)
 	if err != nil {
 		return nil, err
 	}
-	if prod.Cmp(damgard.Params.Q) > 0 {
-		return nil, fmt.Errorf("2 * l * numClients * bound^2 should be smaller than group order")
+	if err := internal.CheckOrderBound(l*numClients, bound, bound, damgard.Params.Q); err != nil {
+		return nil, err
 	}
 	// the bound of the underlying Damgard scheme is set to
 	// the maximum value since the scheme will be used to encrypt
@@ -129,7 +123,7 @@ func NewDamgardMultiPrecomp(numClients, l, modulusLength int, bound *big.Int) (*
 func NewDamgardMultiClientFromParams(bound *big.Int, params *DamgardParams) *DamgardMultiClient {
 	return &DamgardMultiClient{
 		Bound:   bound,
-		Damgard: &Damgard{params},
+		Damgard: &Damgard{Params: params},
 	}
 }
 
@@ -142,7 +136,7 @@ func NewDamgardMultiFromParams(numClients int, bound *big.Int, params *DamgardPa
 	return &DamgardMulti{
 		NumClients: numClients,
 		Bound:      bound,
-		Damgard:    &Damgard{params},
+		Damgard:    &Damgard{Params: params},
 	}
 }
 
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"fmt"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// GobEncode encodes the master secret key for encoding/gob, with S
+// and T serialized together by data.MarshalVectors, whose header holds
+// the number of vectors and their length. The encoding holds the
+// secret key, and should be protected and wiped like the key itself.
+func (k *DamgardSecKey) GobEncode() ([]byte, error) {
+	if len(k.S) != len(k.T) {
+		return nil, fmt.Errorf("vectors of the secret key should be of same length")
+	}
+
+	return data.MarshalVectors([]data.Vector{k.S, k.T})
+}
+
+// GobDecode decodes the master secret key encoded by GobEncode. It
+// returns an error if the input is malformed.
+func (k *DamgardSecKey) GobDecode(b []byte) error {
+	vs, err := data.UnmarshalVectors(b)
+	if err != nil {
+		return err
+	}
+	if len(vs) != 2 {
+		return fmt.Errorf("secret key should consist of 2 vectors, got %d", len(vs))
+	}
+	k.S, k.T = vs[0], vs[1]
+
+	return nil
+}
+
+// Zeroize overwrites every word of the components of S and T with
+// zeros and sets the components to 0, so that the key does not linger
+// in memory after use. Copies of the key made elsewhere, e.g. its
+// encodings, are not affected. The key must not be used afterwards.
+func (k *DamgardSecKey) Zeroize() {
+	for _, v := range []data.Vector{k.S, k.T} {
+		for _, x := range v {
+			if x == nil {
+				continue
+			}
+			// the whole capacity, which may hold former values
+			words := x.Bits()
+			words = words[:cap(words)]
+			for i := range words {
+				words[i] = 0
+			}
+			x.SetInt64(0)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullySec_DamgardSecKeyGob(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, _, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(masterSecKey); err != nil {
+		t.Fatalf("Error during encoding: %v", err)
+	}
+	var decoded fullysec.DamgardSecKey
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Error during decoding: %v", err)
+	}
+	assert.Equal(t, masterSecKey.S, decoded.S)
+	assert.Equal(t, masterSecKey.T, decoded.T)
+
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key1, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	key2, err := damgard.DeriveKey(&decoded, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, key1, key2)
+
+	assert.Error(t, decoded.GobDecode([]byte{1, 2}), "malformed input should be rejected")
+}
+
+func TestFullySec_DamgardSecKeyZeroize(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, _, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	words := masterSecKey.S[0].Bits()
+
+	masterSecKey.Zeroize()
+	for _, v := range []data.Vector{masterSecKey.S, masterSecKey.T} {
+		for _, x := range v {
+			assert.Equal(t, 0, x.Sign(), "components should be zero")
+		}
+	}
+	for _, w := range words {
+		assert.Equal(t, big.Word(0), w, "words should be overwritten")
+	}
+}
@@ -17,11 +17,20 @@
 package fullysec_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/fe"
 	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
 	"github.com/fentec-project/gofe/sample"
 	"github.com/stretchr/testify/assert"
 )
@@ -105,3 +114,557 @@ func TestFullySec_DamgardDDH(t *testing.T) {
 		})
 	}
 }
+
+func TestFullySec_DamgardDecryptUnbounded(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	y := data.NewVector([]*big.Int{big.NewInt(10), big.NewInt(3)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// the encryptor uses a bound larger than the one
+	// assumed by the decryptor
+	params := *damgard.Params
+	params.Bound = big.NewInt(1000)
+	x := data.NewVector([]*big.Int{big.NewInt(300), big.NewInt(-5)})
+	cipher, err := fullysec.NewDamgardFromParams(&params).Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	xy, err := damgard.DecryptUnbounded(cipher, key, y, big.NewInt(10000))
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(2985)), "obtained incorrect inner product")
+}
+
+func TestFullySec_DamgardPrecompModulusLengths(t *testing.T) {
+	// the registered modulus lengths are those of simple.NewDDHPrecomp
+	for _, n := range simple.PrecompModulusLengths() {
+		_, err := fullysec.NewDamgardPrecomp(2, n, big.NewInt(10))
+		assert.NoError(t, err)
+	}
+}
+
+func TestFullySec_DamgardBoundTooLarge(t *testing.T) {
+	bound := new(big.Int).Lsh(big.NewInt(1), 600)
+
+	_, err := fullysec.NewDamgardPrecomp(2, 1024, bound)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	_, err = fullysec.NewDamgard(2, 128, bound)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	_, err = fullysec.NewDamgardMultiPrecomp(3, 2, 1024, new(big.Int).Lsh(big.NewInt(1), 510))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+
+	_, err = fullysec.NewDamgardPrecomp(2, 1000, big.NewInt(10))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, fe.ErrBoundTooLarge), "unrelated error should not be ErrBoundTooLarge")
+}
+
+func TestFullySec_DamgardCiphertextSize(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	_, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+
+	size := damgard.CiphertextSize()
+	// header of 4 bytes and 5 components of 129 bytes
+	assert.Equal(t, 4+5*129, size)
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	b, err := data.MarshalVectors([]data.Vector{cipher})
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	assert.True(t, len(b) <= size, "serialized ciphertext should not exceed the size")
+}
+
+func TestFullySec_DamgardDecryptFull(t *testing.T) {
+	l := 4
+	bound := big.NewInt(1000)
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	keys, err := damgard.DeriveBasisKeys(masterSecKey)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, l, len(keys))
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	x, _ := data.NewRandomVector(l, sampler)
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	xCheck, err := damgard.DecryptFull(cipher, keys)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	for i := range x {
+		assert.Equal(t, 0, x[i].Cmp(xCheck[i]), "decrypted vector does not match")
+	}
+
+	_, err = damgard.DecryptFull(cipher, keys[1:])
+	assert.Error(t, err, "missing keys should be rejected")
+}
+
+// countingSolver computes discrete logarithms with the default
+// solver and counts its invocations.
+type countingSolver struct {
+	dlog.BabyStepGiantStepSolver
+	calls int
+}
+
+func (s *countingSolver) Solve(h, g, p, q, bound *big.Int) (*big.Int, error) {
+	s.calls++
+	return s.BabyStepGiantStepSolver.Solve(h, g, p, q, bound)
+}
+
+func TestFullySec_DamgardWithSolver(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	solver := &countingSolver{}
+	xy, err := damgard.WithSolver(solver).Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	assert.Equal(t, 1, solver.calls, "custom solver should be used")
+
+	xy, err = damgard.WithSolver(solver).DecryptUnbounded(cipher, key, y, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	assert.Equal(t, 2, solver.calls, "custom solver should be used by DecryptUnbounded")
+}
+
+func TestFullySec_DamgardWithTableCache(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	cached := damgard.WithTableCache()
+	for i := 0; i < 3; i++ {
+		xy, err := cached.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	}
+
+	stats := cached.TableStats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should be built once")
+	assert.Equal(t, uint64(2), stats.Reuses, "table should be reused")
+	assert.True(t, stats.Lookups > 0, "lookups should be counted")
+	assert.Equal(t, uint64(0), damgard.TableStats().Builds, "original instance should not cache tables")
+
+	// a ciphertext with a zero component is rejected, not a panic
+	malformed := append(data.Vector{big.NewInt(0)}, cipher[1:]...)
+	_, err = cached.Decrypt(malformed, key, y)
+	assert.Error(t, err)
+	_, err = damgard.DecryptAndCombine([]data.Vector{malformed}, []*fullysec.DamgardDerivedKey{key},
+		[]data.Vector{y}, func(xs []*big.Int) *big.Int { return xs[0] })
+	assert.Error(t, err)
+}
+
+func TestFullySec_DamgardDeriveDiffKey(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	// a and b exceed the bound, but their difference does not
+	a := data.NewVector([]*big.Int{big.NewInt(1000), big.NewInt(-500)})
+	b := data.NewVector([]*big.Int{big.NewInt(995), big.NewInt(-510)})
+	key, err := damgard.DeriveDiffKey(masterSecKey, a, b)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	diff := data.NewVector([]*big.Int{big.NewInt(5), big.NewInt(10)})
+	xy, err := damgard.Decrypt(cipher, key, diff)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-25)), "obtained incorrect inner product")
+
+	_, err = damgard.DeriveDiffKey(masterSecKey, a, data.NewVector([]*big.Int{big.NewInt(0), big.NewInt(0)}))
+	assert.Error(t, err, "difference beyond the bound should be rejected")
+	_, err = damgard.DeriveDiffKey(masterSecKey, a, b[1:])
+	assert.Error(t, err, "vectors of different lengths should be rejected")
+}
+
+func TestFullySec_DamgardClose(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(4)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	cached := damgard.WithTableCache()
+	copied := cached.WithSolver(nil)
+	_, err = cached.Decrypt(cipher, key, y)
+	assert.NoError(t, err)
+	assert.NoError(t, cached.Close())
+	assert.NoError(t, cached.Close(), "closing twice should have no effect")
+
+	_, _, err = cached.GenerateMasterKeys()
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = cached.DeriveKey(masterSecKey, y)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = cached.Encrypt(x, masterPubKey)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = cached.Decrypt(cipher, key, y)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = cached.DecryptUnbounded(cipher, key, y, big.NewInt(1000))
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+
+	// copies made before remain usable
+	xy, err := copied.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(11)), "obtained incorrect inner product")
+}
+
+func TestFullySec_DamgardDeriveKeysFromMatrix(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y, err := data.NewMatrix([]data.Vector{
+		data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)}),
+		data.NewVector([]*big.Int{big.NewInt(-10), big.NewInt(0)}),
+		data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-7)}),
+	})
+	if err != nil {
+		t.Fatalf("Error during matrix creation: %v", err)
+	}
+	keys, err := damgard.DeriveKeysFromMatrix(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, 3, len(keys))
+
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	for i, expected := range []int64{-5, -30, 37} {
+		xy, err := damgard.Decrypt(cipher, keys[i], y[i])
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, expected, xy.Int64(), "obtained incorrect inner product")
+	}
+
+	y[2][1] = big.NewInt(11)
+	keys, err = damgard.DeriveKeysFromMatrix(masterSecKey, y)
+	assert.Nil(t, keys)
+	violation, ok := err.(*data.BoundViolationError)
+	if assert.True(t, ok, "expected *data.BoundViolationError, got %v", err) {
+		assert.Equal(t, 2, violation.Row)
+		assert.Equal(t, 1, violation.Index)
+	}
+}
+
+func TestFullySec_DamgardCheckCiphertextShape(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	_, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.NoError(t, damgard.CheckCiphertextShape(cipher))
+
+	assert.Error(t, damgard.CheckCiphertextShape(cipher[:4]), "ciphertext should have l + 2 components")
+	malformed := cipher.Copy()
+	malformed[2] = new(big.Int).Set(damgard.Params.P)
+	assert.Error(t, damgard.CheckCiphertextShape(malformed), "components should be smaller than P")
+	malformed[2] = big.NewInt(0)
+	assert.Error(t, damgard.CheckCiphertextShape(malformed), "components should be nonzero")
+}
+
+func TestFullySec_DamgardEncryptParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	l := 300
+	bound := big.NewInt(10)
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	x, _ := data.NewRandomVector(l, sampler)
+	y, _ := data.NewRandomVector(l, sampler)
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, l+2, len(cipher))
+
+	// every coordinate is at its place: decryption with each basis
+	// key recovers the corresponding coordinate of x
+	xy, err := damgard.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	expected, _ := x.Dot(y)
+	assert.Equal(t, 0, xy.Cmp(expected), "obtained incorrect inner product")
+	for _, i := range []int{0, 75, 150, 299} {
+		e := data.NewConstantVector(l, big.NewInt(0))
+		e[i] = big.NewInt(1)
+		key, err := damgard.DeriveKey(masterSecKey, e)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		xi, err := damgard.Decrypt(cipher, key, e)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xi.Cmp(x[i]), "coordinate %d", i)
+	}
+}
+
+func TestFullySec_DamgardDecryptWithContext(t *testing.T) {
+	damgard, err := fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(1<<21))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(1 << 21)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(-3)})
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// the search is cancelled while the range of 2^43 is searched
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = damgard.DecryptWithContext(ctx, cipher, key, y)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "decryption should stop promptly")
+
+	// already cancelled
+	_, err = damgard.DecryptWithContext(ctx, cipher, key, y)
+	assert.Equal(t, context.Canceled, err)
+
+	// the inner product is found before the deadline with a smaller bound
+	damgard, err = fullysec.NewDamgardPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err = damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	key, err = damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	x = data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(2)})
+	cipher, err = damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	xy, err := damgard.DecryptWithContext(ctx, cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-41), xy.Int64(), "obtained incorrect inner product")
+}
+
+func TestFullySec_DamgardEncryptLengths(t *testing.T) {
+	l := 3
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	_, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	sampler := sample.NewUniformRange(big.NewInt(-10), big.NewInt(11))
+
+	// every combination of lengths up to 2l, none of them may panic
+	for xLen := 0; xLen <= 2*l; xLen++ {
+		for keyLen := 0; keyLen <= 2*l; keyLen++ {
+			x, _ := data.NewRandomVector(xLen, sampler)
+			mpk := append(data.Vector{}, masterPubKey...)
+			for len(mpk) < keyLen {
+				mpk = append(mpk, masterPubKey[0])
+			}
+			mpk = mpk[:keyLen]
+
+			cipher, err := damgard.Encrypt(x, mpk)
+			switch {
+			case keyLen != l:
+				assert.True(t, errors.Is(err, internal.ErrMalformedPubKey),
+					"x of length %d, key of length %d: expected malformed public key, got %v", xLen, keyLen, err)
+			case xLen != l:
+				assert.True(t, errors.Is(err, internal.ErrMalformedInput),
+					"x of length %d, key of length %d: expected malformed input, got %v", xLen, keyLen, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, damgard.CiphertextSize() > 0, len(cipher) > l)
+			}
+		}
+	}
+
+	_, err = damgard.Encrypt(nil, nil)
+	assert.True(t, errors.Is(err, internal.ErrMalformedPubKey))
+}
+
+func TestFullySec_DamgardDecryptLengths(t *testing.T) {
+	l := 3
+	damgard, err := fullysec.NewDamgardPrecomp(l, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := damgard.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewConstantVector(l, big.NewInt(2))
+	y := data.NewConstantVector(l, big.NewInt(3))
+	key, err := damgard.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := damgard.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// truncated and extended ciphertexts must be rejected, not panic
+	for n := 0; n <= 2*len(cipher); n++ {
+		if n == len(cipher) {
+			continue
+		}
+		c := append(data.Vector{}, cipher...)
+		for len(c) < n {
+			c = append(c, cipher[0])
+		}
+		_, err := damgard.Decrypt(c[:n], key, y)
+		assert.True(t, errors.Is(err, internal.ErrMalformedCipher),
+			"ciphertext of length %d: expected malformed ciphertext, got %v", n, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("got %d components, expected %d", n, l+2))
+	}
+
+	_, err = damgard.Decrypt(cipher, key, y[:l-1])
+	assert.True(t, errors.Is(err, internal.ErrMalformedInput), "expected malformed input, got %v", err)
+
+	xy, err := damgard.Decrypt(cipher, key, y)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(18), xy)
+}
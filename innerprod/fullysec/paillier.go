@@ -221,11 +221,43 @@ func (s *Paillier) Encrypt(x, masterPubKey data.Vector) (data.Vector, error) {
 // Decrypt accepts the encrypted vector, functional encryption key, and
 // a vector y. It returns the inner product of x and y.
 func (s *Paillier) Decrypt(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	ret, err := s.DecryptModOrder(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the return value is negative this is seen as the above ret being
+	// greater than n/2; in this case ret = ret - n
+	nHalf := new(big.Int).Quo(s.Params.N, big.NewInt(2))
+	if ret.Cmp(nHalf) == 1 {
+		ret.Sub(ret, s.Params.N)
+	}
+
+	return ret, nil
+}
+
+// DecryptModOrder accepts the encrypted vector, functional encryption
+// key, and a vector y. It returns the inner product of x and y reduced
+// modulo N, i.e. as an element of [0, N), where N is the order of the
+// message space.
+//
+// In this scheme the inner product is recovered algebraically rather
+// than by searching for a discrete logarithm, thus the result modulo N
+// is correct for any x, and the bound on x is only needed by Decrypt
+// to interpret the result as a (possibly negative) integer. Schemes
+// based on the DDH assumption (e.g. simple.DDH and Damgard) do not
+// offer this: they encode the inner product in the exponent of a group
+// element, and recovering it modulo the group order is exactly the
+// discrete logarithm problem, feasible only for bounded values.
+func (s *Paillier) DecryptModOrder(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
 	if s.Params.BoundX != nil {
 		if err := y.CheckBound(s.Params.BoundY); err != nil {
 			return nil, err
 		}
 	}
+	if len(cipher) != len(y)+1 {
+		return nil, internal.ErrMalformedCipher
+	}
 
 	// tmp value cX is calculated as (prod_{i=1 to l} c_i^y_i) * c_0^(-key) in Z_n^2
 	keyNeg := new(big.Int).Neg(key)
@@ -240,13 +272,6 @@ func (s *Paillier) Decrypt(cipher data.Vector, key *big.Int, y data.Vector) (*bi
 	// decryption is calculated as (cX-1 mod n^2)/n
 	cX.Sub(cX, big.NewInt(1))
 	cX.Mod(cX, s.Params.NSquare)
-	ret := new(big.Int).Quo(cX, s.Params.N)
-	// if the return value is negative this is seen as the above ret being
-	// greater than n/2; in this case ret = ret - n
-	nHalf := new(big.Int).Quo(s.Params.N, big.NewInt(2))
-	if ret.Cmp(nHalf) == 1 {
-		ret.Sub(ret, s.Params.N)
-	}
 
-	return ret, nil
+	return cX.Quo(cX, s.Params.N), nil
 }
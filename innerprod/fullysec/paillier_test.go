@@ -80,3 +80,36 @@ func TestFullySec_Paillier(t *testing.T) {
 	}
 	assert.Equal(t, xy.Cmp(xyCheck), 0, "Original and decrypted values should match")
 }
+
+func TestFullySec_PaillierDecryptModOrder(t *testing.T) {
+	bound := big.NewInt(1000)
+	paillier, err := fullysec.NewPaillier(2, 128, 512, bound, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := paillier.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(-1000), big.NewInt(3)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(-2)})
+	key, err := paillier.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	ciphertext, err := paillier.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	xy, err := paillier.DecryptModOrder(ciphertext, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	xyCheck := new(big.Int).Mod(big.NewInt(-7006), paillier.Params.N)
+	assert.Equal(t, 0, xy.Cmp(xyCheck), "inner product modulo N does not match")
+
+	_, err = paillier.DecryptModOrder(ciphertext[:2], key, y)
+	assert.Error(t, err, "malformed ciphertext should be rejected")
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fullysec
+
+import (
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/innerprod/simple"
+)
+
+func init() {
+	precomp := simple.PrecompModulusLengths()
+
+	fe.Register(fe.SchemeInfo{Name: "fullysec.Damgard", Assumption: fe.DDH, FullySecure: true, ModulusLengths: precomp})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.DamgardMulti", Assumption: fe.DDH, FullySecure: true, ModulusLengths: precomp})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.DamgardDecMulti", Assumption: fe.DDH, FullySecure: true, ModulusLengths: precomp})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.LWE", Assumption: fe.LWE, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.Paillier", Assumption: fe.DCR, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.PaillierMulti", Assumption: fe.DCR, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.DMCFE", Assumption: fe.SXDH, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.FHIPE", Assumption: fe.SXDH, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.FHMultiIPE", Assumption: fe.SXDH, FullySecure: true})
+	fe.Register(fe.SchemeInfo{Name: "fullysec.PartFHIPE", Assumption: fe.SXDH, FullySecure: true})
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package innerprod
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// KeyManager holds named scheme instances together with their master
+// keys and the functional encryption keys derived with them, so that
+// callers refer to schemes by name and to derived keys by their
+// identifiers. It is safe for concurrent use.
+//
+// The manager holds the master secret keys in memory, thus it should
+// only be used by the party entitled to them.
+type KeyManager struct {
+	mu      sync.RWMutex
+	schemes map[string]*managedScheme
+	// number of keys derived so far, used for key identifiers
+	derived uint64
+}
+
+// managedScheme is a scheme registered with a KeyManager.
+type managedScheme struct {
+	scheme       Scheme
+	masterSecKey interface{}
+	masterPubKey data.Vector
+	// derived keys by their identifiers
+	keys map[string]*managedKey
+}
+
+// managedKey is a derived key together with the vector y it was
+// derived for.
+type managedKey struct {
+	key interface{}
+	y   data.Vector
+}
+
+// NewKeyManager returns a KeyManager without any schemes.
+func NewKeyManager() *KeyManager {
+	return &KeyManager{
+		schemes: make(map[string]*managedScheme),
+	}
+}
+
+// Register adds the scheme instance with its master keys under the
+// given name, e.g. a scheme obtained by NewAuto, NewDDHScheme or
+// NewDamgardScheme. It returns an error if the name is already taken.
+func (m *KeyManager) Register(name string, scheme Scheme, masterSecKey interface{}, masterPubKey data.Vector) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.schemes[name]; ok {
+		return fmt.Errorf("scheme %q is already registered", name)
+	}
+	m.schemes[name] = &managedScheme{
+		scheme:       scheme,
+		masterSecKey: masterSecKey,
+		masterPubKey: masterPubKey,
+		keys:         make(map[string]*managedKey),
+	}
+
+	return nil
+}
+
+// MasterPubKey returns the master public key of the named scheme.
+// It returns an error if no scheme is registered under the name.
+func (m *KeyManager) MasterPubKey(name string) (data.Vector, error) {
+	s, err := m.scheme(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.masterPubKey.Copy(), nil
+}
+
+// Encrypt encrypts x with the named scheme and its master public key.
+// It returns an error if no scheme is registered under the name or
+// encryption failed.
+func (m *KeyManager) Encrypt(name string, x data.Vector) (data.Vector, error) {
+	s, err := m.scheme(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scheme.Encrypt(x, s.masterPubKey)
+}
+
+// DeriveKey derives the functional encryption key for y with the named
+// scheme and its master secret key, and keeps it in the manager. It
+// returns the identifier of the key, to be passed to Decrypt together
+// with the same y. It returns an error if no scheme is registered
+// under the name or the key could not be derived.
+func (m *KeyManager) DeriveKey(name string, y data.Vector) (string, error) {
+	s, err := m.scheme(name)
+	if err != nil {
+		return "", err
+	}
+	key, err := s.scheme.DeriveKey(s.masterSecKey, y)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.derived++
+	keyID := fmt.Sprintf("%s/%d", name, m.derived)
+	s.keys[keyID] = &managedKey{key: key, y: y.Copy()}
+
+	return keyID, nil
+}
+
+// Decrypt decrypts the inner product of x and y from the ciphertext
+// with the named scheme and the derived key with identifier keyID. It
+// returns an error if no scheme is registered under the name, the key
+// was not derived by the named scheme, y is not the vector the key was
+// derived for, or decryption failed.
+func (m *KeyManager) Decrypt(name string, cipher data.Vector, keyID string, y data.Vector) (*big.Int, error) {
+	s, err := m.scheme(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	k, ok := s.keys[keyID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("key %q does not belong to scheme %q", keyID, name)
+	}
+	if !equalVectors(k.y, y) {
+		return nil, fmt.Errorf("key %q was derived for a different vector y", keyID)
+	}
+
+	return s.scheme.Decrypt(cipher, k.key, y)
+}
+
+// scheme returns the scheme registered under the name.
+func (m *KeyManager) scheme(name string) (*managedScheme, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("scheme %q is not registered", name)
+	}
+
+	return s, nil
+}
+
+// equalVectors reports whether the vectors a and b are equal.
+func equalVectors(a, b data.Vector) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package innerprod_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyManager(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	damgard, err := fullysec.NewDamgardPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+
+	m := innerprod.NewKeyManager()
+	for name, scheme := range map[string]innerprod.Scheme{
+		"ddh":     innerprod.NewDDHScheme(ddh),
+		"damgard": innerprod.NewDamgardScheme(damgard),
+	} {
+		masterSecKey, masterPubKey, err := scheme.GenerateMasterKeys()
+		if err != nil {
+			t.Fatalf("Error during master key generation: %v", err)
+		}
+		assert.NoError(t, m.Register(name, scheme, masterSecKey, masterPubKey))
+		assert.Error(t, m.Register(name, scheme, masterSecKey, masterPubKey), "name should be unique")
+	}
+
+	xDDH := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-2)})
+	yDDH := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(4)})
+	xDamgard := data.NewVector([]*big.Int{big.NewInt(10), big.NewInt(-20), big.NewInt(30)})
+	yDamgard := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(2)})
+
+	cipherDDH, err := m.Encrypt("ddh", xDDH)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	cipherDamgard, err := m.Encrypt("damgard", xDamgard)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	keyDDH, err := m.DeriveKey("ddh", yDDH)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	keyDamgard, err := m.DeriveKey("damgard", yDamgard)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.NotEqual(t, keyDDH, keyDamgard)
+
+	xy, err := m.Decrypt("ddh", cipherDDH, keyDDH, yDDH)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-5)), "obtained incorrect inner product")
+	xy, err = m.Decrypt("damgard", cipherDamgard, keyDamgard, yDamgard)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(50)), "obtained incorrect inner product")
+
+	_, err = m.Decrypt("ddh", cipherDDH, keyDamgard, yDDH)
+	assert.Error(t, err, "key of another scheme should be rejected")
+	_, err = m.Decrypt("ddh", cipherDDH, keyDDH, data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(3)}))
+	assert.Error(t, err, "different y should be rejected")
+	_, err = m.Encrypt("unknown", xDDH)
+	assert.Error(t, err)
+	_, err = m.DeriveKey("unknown", yDDH)
+	assert.Error(t, err)
+	_, err = m.Decrypt("unknown", cipherDDH, keyDDH, yDDH)
+	assert.Error(t, err)
+	_, err = m.MasterPubKey("unknown")
+	assert.Error(t, err)
+	mpk, err := m.MasterPubKey("ddh")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(mpk))
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mcfe includes a labeled multi-client functional encryption
+// scheme for inner products.
+//
+// In a multi-client scheme each of n clients holds its own secret key
+// and encrypts a single value x_i under a label (e.g. a timestamp).
+// Given the ciphertexts of all the clients for the same label and a
+// functional key for a vector y, a decryptor obtains the weighted sum
+// <x, y> = sum_i x_i * y_i, but learns nothing else about the values.
+// Ciphertexts for different labels cannot be combined.
+//
+// The implementation is based on the DDH instantiation of the paper
+// by Chotard, Dufour Sans, Gay, Phan and Pointcheval:
+// "Decentralized Multi-Client Functional Encryption for Inner Product"
+// (see https://eprint.iacr.org/2017/989.pdf), in the group Z_p*
+// of the DDH based inner product schemes.
+package mcfe
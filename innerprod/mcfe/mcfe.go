@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcfe
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/dlog"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// MCFEParams represents configuration parameters for the MCFE scheme
+// instance.
+type MCFEParams struct {
+	// number of clients
+	NumClients int
+	// The value by which the values x_i of the clients and
+	// coordinates of vectors y are bounded.
+	Bound *big.Int
+	// Generator of a cyclic group Z_P: G^(Q) = 1 (mod P).
+	G *big.Int
+	// Modulus - we are operating in a cyclic group Z_P.
+	P *big.Int
+	// Order of the generator G.
+	Q *big.Int
+}
+
+// MCFE represents a labeled multi-client scheme instantiated from
+// the DDH assumption.
+type MCFE struct {
+	Params *MCFEParams
+}
+
+// NewMCFE configures a new instance of the scheme. It accepts the
+// number of clients, the bit length of the modulus (we are operating
+// in the Z_p group), and a bound by which the values of the clients
+// and coordinates of vectors y are bounded.
+//
+// It returns an error in case the scheme could not be properly
+// configured, or if precondition 2 * numClients * bound² is > order
+// of the cyclic group, in which case the error wraps
+// fe.ErrBoundTooLarge.
+func NewMCFE(numClients, modulusLength int, bound *big.Int) (*MCFE, error) {
+	key, err := keygen.NewElGamal(modulusLength)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMCFE(numClients, bound, key.G, key.P, key.Q)
+}
+
+// NewMCFEPrecomp configures a new instance of the scheme based on
+// precomputed prime numbers and generators, the same as used by
+// simple.NewDDHPrecomp. The modulus length should be one of values
+// 1024, 1536, 2048, 2560, 3072, or 4096.
+//
+// It returns an error in case the scheme could not be properly
+// configured, or if precondition 2 * numClients * bound² is > order
+// of the cyclic group, in which case the error wraps
+// fe.ErrBoundTooLarge.
+func NewMCFEPrecomp(numClients, modulusLength int, bound *big.Int) (*MCFE, error) {
+	ddh, err := simple.NewDDHPrecomp(1, modulusLength, big.NewInt(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return newMCFE(numClients, bound, ddh.Params.G, ddh.Params.P, ddh.Params.Q)
+}
+
+func newMCFE(numClients int, bound, g, p, q *big.Int) (*MCFE, error) {
+	if err := internal.CheckOrderBound(numClients, bound, bound, q); err != nil {
+		return nil, err
+	}
+
+	return &MCFE{
+		Params: &MCFEParams{
+			NumClients: numClients,
+			Bound:      bound,
+			G:          g,
+			P:          p,
+			Q:          q,
+		},
+	}, nil
+}
+
+// NewMCFEFromParams takes configuration parameters of an existing
+// MCFE scheme instance, and reconstructs the scheme with same
+// configuration parameters. It returns a new MCFE instance.
+func NewMCFEFromParams(params *MCFEParams) *MCFE {
+	return &MCFE{
+		Params: params,
+	}
+}
+
+// GenerateClientKeys generates the secret keys of all the clients,
+// each of them a random vector s_i of length 2. The i-th key is to be
+// given to the i-th client, while all of them are needed to derive
+// functional keys. It returns an error in case the keys could not be
+// generated.
+func (m *MCFE) GenerateClientKeys() ([]data.Vector, error) {
+	sampler := sample.NewUniform(m.Params.Q)
+	keys := make([]data.Vector, m.Params.NumClients)
+	for i := range keys {
+		s, err := data.NewRandomVector(2, sampler)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = s
+	}
+
+	return keys, nil
+}
+
+// Encrypt encrypts value x of a client under the label with the
+// client's secret key. The ciphertext is g^(<u, s_i> + x), where
+// g^u = (g^u_1, g^u_2) is obtained by hashing the label.
+//
+// A client must encrypt at most one value under each label, otherwise
+// the difference of the values is revealed. It returns an error if x
+// is out of bound or the secret key is malformed.
+func (m *MCFE) Encrypt(x *big.Int, label string, clientSecKey data.Vector) (*big.Int, error) {
+	if new(big.Int).Abs(x).Cmp(m.Params.Bound) > 0 {
+		return nil, fmt.Errorf("value should not be greater than bound")
+	}
+	if len(clientSecKey) != 2 {
+		return nil, internal.ErrMalformedSecKey
+	}
+
+	u, err := m.hashLabel(label)
+	if err != nil {
+		return nil, err
+	}
+	bases := []*big.Int{u[0], u[1], m.Params.G}
+	exps := []*big.Int{clientSecKey[0], clientSecKey[1], x}
+
+	return internal.ModExpProduct(bases, exps, m.Params.P), nil
+}
+
+// DeriveKey takes the secret keys of all the clients and a vector y
+// with a coordinate for each client, and returns the functional
+// encryption key sum_i y_i * s_i. It returns an error if y is out of
+// bound or its length does not match the number of keys.
+func (m *MCFE) DeriveKey(clientSecKeys []data.Vector, y data.Vector) (data.Vector, error) {
+	if err := y.CheckBound(m.Params.Bound); err != nil {
+		return nil, err
+	}
+	if len(y) != len(clientSecKeys) {
+		return nil, fmt.Errorf("y should have a coordinate for each client")
+	}
+
+	key := data.NewConstantVector(2, big.NewInt(0))
+	for i, s := range clientSecKeys {
+		if len(s) != 2 {
+			return nil, internal.ErrMalformedSecKey
+		}
+		key = key.Add(s.MulScalar(y[i]))
+	}
+
+	return key.Mod(m.Params.Q), nil
+}
+
+// Decrypt accepts the ciphertexts of all the clients encrypted under
+// the label, the functional encryption key for y, and y. It returns
+// the inner product of the values x_i and y, or an error if the
+// inner product could not be found.
+func (m *MCFE) Decrypt(ciphers []*big.Int, label string, key, y data.Vector) (*big.Int, error) {
+	if err := y.CheckBound(m.Params.Bound); err != nil {
+		return nil, err
+	}
+	if len(ciphers) != len(y) {
+		return nil, internal.ErrMalformedCipher
+	}
+	if len(key) != 2 {
+		return nil, internal.ErrMalformedDecKey
+	}
+
+	u, err := m.hashLabel(label)
+	if err != nil {
+		return nil, err
+	}
+
+	// prod_i c_i^y_i / (g^u_1)^key_1 / (g^u_2)^key_2 = g^<x, y>
+	bases := append([]*big.Int{u[0], u[1]}, ciphers...)
+	exps := append([]*big.Int{new(big.Int).Neg(key[0]), new(big.Int).Neg(key[1])}, y...)
+	r := internal.ModExpProduct(bases, exps, m.Params.P)
+
+	bound := new(big.Int).Exp(m.Params.Bound, big.NewInt(2), nil)
+	bound.Mul(bound, big.NewInt(int64(len(y))))
+	calc, err := dlog.NewCalc().InZp(m.Params.P, m.Params.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	return calc.WithNeg().WithBound(bound).BabyStepGiantStep(r, m.Params.G)
+}
+
+// hashLabel hashes the label to a pair of elements of the subgroup
+// generated by G, with discrete logarithms unknown to anyone. The
+// hash is squared to land in the subgroup of quadratic residues,
+// which is the subgroup generated by G since P is a safe prime.
+func (m *MCFE) hashLabel(label string) ([]*big.Int, error) {
+	u := make([]*big.Int, 2)
+	buf := make([]byte, (m.Params.P.BitLen()+7)/8+16)
+	for i := range u {
+		seed := sha256.Sum256([]byte(strconv.Itoa(i) + " " + label))
+		if _, err := io.ReadFull(internal.NewDetReader(seed[:]), buf); err != nil {
+			return nil, err
+		}
+		u[i] = new(big.Int).SetBytes(buf)
+		u[i].Exp(u[i], big.NewInt(2), m.Params.P)
+		if u[i].Sign() == 0 {
+			return nil, fmt.Errorf("label could not be hashed to the group")
+		}
+	}
+
+	return u, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcfe_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/innerprod/mcfe"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCFE(t *testing.T) {
+	numClients := 5
+	bound := big.NewInt(1000)
+	m, err := mcfe.NewMCFEPrecomp(numClients, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	clientKeys, err := m.GenerateClientKeys()
+	if err != nil {
+		t.Fatalf("Error during client key generation: %v", err)
+	}
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	x, _ := data.NewRandomVector(numClients, sampler)
+	y, _ := data.NewRandomVector(numClients, sampler)
+	label := "2026-10-16T12:00"
+
+	// each client encrypts its value with its own key
+	ciphers := make([]*big.Int, numClients)
+	for i := range ciphers {
+		ciphers[i], err = m.Encrypt(x[i], label, clientKeys[i])
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+	}
+
+	key, err := m.DeriveKey(clientKeys, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := m.Decrypt(ciphers, label, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	xyCheck, _ := x.Dot(y)
+	assert.Equal(t, 0, xy.Cmp(xyCheck), "obtained incorrect inner product")
+
+	// ciphertexts are bound to the label
+	_, err = m.Decrypt(ciphers, "another label", key, y)
+	assert.Error(t, err, "decryption under a wrong label should fail")
+
+	_, err = m.Encrypt(big.NewInt(1001), label, clientKeys[0])
+	assert.Error(t, err, "value out of bound should be rejected")
+	_, err = m.Decrypt(ciphers[1:], label, key, y)
+	assert.Error(t, err, "missing ciphertexts should be rejected")
+}
+
+func TestMCFE_NewMCFE(t *testing.T) {
+	_, err := mcfe.NewMCFEPrecomp(2, 1024, new(big.Int).Lsh(big.NewInt(1), 600))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+
+	m, err := mcfe.NewMCFE(2, 128, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	clientKeys, err := m.GenerateClientKeys()
+	if err != nil {
+		t.Fatalf("Error during client key generation: %v", err)
+	}
+	c0, _ := m.Encrypt(big.NewInt(3), "label", clientKeys[0])
+	c1, _ := m.Encrypt(big.NewInt(-4), "label", clientKeys[1])
+	y := data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(5)})
+	key, _ := m.DeriveKey(clientKeys, y)
+	xy, err := m.Decrypt([]*big.Int{c0, c1}, "label", key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-14)), "obtained incorrect inner product")
+}
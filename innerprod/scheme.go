@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package innerprod
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// SecLevel denotes the security level of a scheme.
+type SecLevel int
+
+const (
+	// Selective denotes selective security under chosen-plaintext
+	// attacks (s-IND-CPA), offered by the schemes of package simple.
+	Selective SecLevel = iota
+	// Full denotes adaptive security under chosen-plaintext attacks
+	// (IND-CPA), offered by the schemes of package fullysec.
+	Full
+)
+
+// Scheme is the common interface of the public key inner product
+// schemes over the integers. Master secret keys and derived keys are
+// scheme specific and are only to be passed back to the scheme that
+// produced them.
+type Scheme interface {
+	// GenerateMasterKeys generates a pair of master secret key and
+	// master public key.
+	GenerateMasterKeys() (interface{}, data.Vector, error)
+	// DeriveKey derives the functional encryption key for y.
+	DeriveKey(masterSecKey interface{}, y data.Vector) (interface{}, error)
+	// Encrypt encrypts x with the master public key.
+	Encrypt(x, masterPubKey data.Vector) (data.Vector, error)
+	// Decrypt returns the inner product of x and y.
+	Decrypt(cipher data.Vector, key interface{}, y data.Vector) (*big.Int, error)
+}
+
+// NewAuto configures a new instance of a DDH based scheme with the
+// given security level: fullysec.Damgard for Full and simple.DDH for
+// Selective security. Damgard offers adaptive security at the cost of
+// larger keys and ciphertexts and slower operations. It accepts the
+// length of input vectors l, the bit length of the modulus, and a
+// bound by which coordinates of input vectors are bounded. Precomputed
+// groups are used for the modulus lengths supported by the Precomp
+// constructors, otherwise a new group is generated.
+//
+// It returns an error in case the scheme could not be properly
+// configured, see NewDDH and NewDamgard.
+func NewAuto(l, modulusLength int, bound *big.Int, securityLevel SecLevel) (Scheme, error) {
+	precomp := simple.SupportsPrecomp(modulusLength)
+	switch securityLevel {
+	case Selective:
+		var ddh *simple.DDH
+		var err error
+		if precomp {
+			ddh, err = simple.NewDDHPrecomp(l, modulusLength, bound)
+		} else {
+			ddh, err = simple.NewDDH(l, modulusLength, bound)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewDDHScheme(ddh), nil
+	case Full:
+		var damgard *fullysec.Damgard
+		var err error
+		if precomp {
+			damgard, err = fullysec.NewDamgardPrecomp(l, modulusLength, bound)
+		} else {
+			damgard, err = fullysec.NewDamgard(l, modulusLength, bound)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewDamgardScheme(damgard), nil
+	default:
+		return nil, fmt.Errorf("unknown security level %d", securityLevel)
+	}
+}
+
+// NewDDHScheme returns the scheme instance d behind the Scheme
+// interface. The master secret keys are data.Vector and the derived
+// keys *big.Int.
+func NewDDHScheme(d *simple.DDH) Scheme {
+	return &ddhScheme{d}
+}
+
+// NewDamgardScheme returns the scheme instance d behind the Scheme
+// interface. The master secret keys are *fullysec.DamgardSecKey and
+// the derived keys *fullysec.DamgardDerivedKey.
+func NewDamgardScheme(d *fullysec.Damgard) Scheme {
+	return &damgardScheme{d}
+}
+
+// ddhScheme adapts simple.DDH to the Scheme interface.
+type ddhScheme struct {
+	*simple.DDH
+}
+
+func (s *ddhScheme) GenerateMasterKeys() (interface{}, data.Vector, error) {
+	// a nil key must not be returned as a non-nil interface
+	masterSecKey, masterPubKey, err := s.DDH.GenerateMasterKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+	return masterSecKey, masterPubKey, nil
+}
+
+func (s *ddhScheme) DeriveKey(masterSecKey interface{}, y data.Vector) (interface{}, error) {
+	msk, ok := masterSecKey.(data.Vector)
+	if !ok {
+		return nil, internal.ErrMalformedSecKey
+	}
+	key, err := s.DDH.DeriveKey(msk, y)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *ddhScheme) Encrypt(x, masterPubKey data.Vector) (data.Vector, error) {
+	return s.DDH.Encrypt(x, masterPubKey)
+}
+
+func (s *ddhScheme) Decrypt(cipher data.Vector, key interface{}, y data.Vector) (*big.Int, error) {
+	k, ok := key.(*big.Int)
+	if !ok {
+		return nil, internal.ErrMalformedDecKey
+	}
+	return s.DDH.Decrypt(cipher, k, y)
+}
+
+// damgardScheme adapts fullysec.Damgard to the Scheme interface.
+type damgardScheme struct {
+	*fullysec.Damgard
+}
+
+func (s *damgardScheme) GenerateMasterKeys() (interface{}, data.Vector, error) {
+	// a nil key must not be returned as a non-nil interface
+	masterSecKey, masterPubKey, err := s.Damgard.GenerateMasterKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+	return masterSecKey, masterPubKey, nil
+}
+
+func (s *damgardScheme) DeriveKey(masterSecKey interface{}, y data.Vector) (interface{}, error) {
+	msk, ok := masterSecKey.(*fullysec.DamgardSecKey)
+	if !ok {
+		return nil, internal.ErrMalformedSecKey
+	}
+	key, err := s.Damgard.DeriveKey(msk, y)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *damgardScheme) Decrypt(cipher data.Vector, key interface{}, y data.Vector) (*big.Int, error) {
+	k, ok := key.(*fullysec.DamgardDerivedKey)
+	if !ok {
+		return nil, internal.ErrMalformedDecKey
+	}
+	return s.Damgard.Decrypt(cipher, k, y)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package innerprod_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuto(t *testing.T) {
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-2), big.NewInt(3)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(-6)})
+
+	for _, level := range []innerprod.SecLevel{innerprod.Selective, innerprod.Full} {
+		scheme, err := innerprod.NewAuto(3, 1024, big.NewInt(10), level)
+		if err != nil {
+			t.Fatalf("Error during scheme creation: %v", err)
+		}
+		masterSecKey, masterPubKey, err := scheme.GenerateMasterKeys()
+		if err != nil {
+			t.Fatalf("Error during master key generation: %v", err)
+		}
+		if level == innerprod.Full {
+			assert.IsType(t, &fullysec.DamgardSecKey{}, masterSecKey, "Damgard should be chosen")
+		} else {
+			assert.IsType(t, data.Vector{}, masterSecKey, "DDH should be chosen")
+		}
+		key, err := scheme.DeriveKey(masterSecKey, y)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		cipher, err := scheme.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		xy, err := scheme.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-24)), "obtained incorrect inner product")
+
+		_, err = scheme.DeriveKey(key, y)
+		assert.Error(t, err, "master secret key of a wrong type should be rejected")
+		// failed derivation must return a nil interface
+		key, err = scheme.DeriveKey(masterSecKey, y.MulScalar(big.NewInt(100)))
+		assert.Error(t, err, "y exceeding the bound should be rejected")
+		assert.True(t, key == nil, "key should be nil on error, got %#v", key)
+		_, err = scheme.Decrypt(cipher, masterSecKey, y)
+		assert.Error(t, err, "derived key of a wrong type should be rejected")
+	}
+
+	_, err := innerprod.NewAuto(3, 1024, big.NewInt(10), innerprod.SecLevel(42))
+	assert.Error(t, err, "unknown security level should be rejected")
+}
@@ -17,12 +17,17 @@
 package simple
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math/big"
+	"math/bits"
+	"time"
 
 	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/fe"
 	"github.com/fentec-project/gofe/internal"
-	"github.com/fentec-project/gofe/internal/dlog"
 	"github.com/fentec-project/gofe/internal/keygen"
 	"github.com/fentec-project/gofe/sample"
 )
@@ -33,12 +38,18 @@ type DDHParams struct {
 	L int
 	// The value by which coordinates of input vectors x and y are bounded.
 	Bound *big.Int
+	// The value by which coordinates of vectors y are bounded,
+	// if different from Bound; nil means Bound.
+	BoundY *big.Int
 	// Generator of a cyclic group Z_P: G^(Q) = 1 (mod P).
 	G *big.Int
 	// Modulus - we are operating in a cyclic group Z_P.
 	P *big.Int
 	// Order of the generator G.
 	Q *big.Int
+	// Number of decimal digits by which fixed-point inputs are
+	// scaled to integers, 0 when the inputs are integers.
+	Scale int
 }
 
 // DDH represents a scheme instantiated from the DDH assumption,
@@ -47,6 +58,22 @@ type DDHParams struct {
 // "Simple Functional Encryption Schemes for Inner Products".
 type DDH struct {
 	Params *DDHParams
+	// tracker of the recently used encryption randomness,
+	// nil if disabled
+	nonceGuard *nonceGuard
+	// key of the PRF deriving randomness in EncryptConvergent
+	convergenceKey *secretKey
+	// solver of the discrete logarithm in decryption,
+	// dlog.BabyStepGiantStepSolver if nil
+	solver dlog.Solver
+	// cache of baby-step tables used by the solver, nil if not enabled
+	tables *dlog.TableCache
+	// cache of decrypted inner products, nil if not enabled
+	results *resultCache
+	// source of randomness, crypto/rand.Reader if nil
+	random io.Reader
+	// set to 1 by Close
+	closed uint32
 }
 
 // NewDDH configures a new instance of the scheme.
@@ -55,16 +82,16 @@ type DDH struct {
 // coordinates of input vectors are bounded.
 //
 // It returns an error in case the scheme could not be properly
-// configured, or if precondition l * bound² is >= order of the cyclic
-// group.
+// configured, or if precondition 2 * l * bound² is > order of the cyclic
+// group, in which case the error wraps fe.ErrBoundTooLarge.
 func NewDDH(l, modulusLength int, bound *big.Int) (*DDH, error) {
 	key, err := keygen.NewElGamal(modulusLength)
 	if err != nil {
 		return nil, err
 	}
 
-	if new(big.Int).Mul(big.NewInt(int64(2*l)), new(big.Int).Exp(bound, big.NewInt(2), big.NewInt(0))).Cmp(key.Q) > 0 {
-		return nil, fmt.Errorf("2 * l * bound^2 should be smaller than group order")
+	if err := internal.CheckOrderBound(l, bound, bound, key.Q); err != nil {
+		return nil, err
 	}
 
 	sip := DDH{
@@ -80,6 +107,109 @@ func NewDDH(l, modulusLength int, bound *big.Int) (*DDH, error) {
 	return &sip, nil
 }
 
+// NewDDHBits configures a new instance of the scheme like NewDDH, with
+// the bound on coordinates of input vectors set to 2^boundBits.
+//
+// Before generating the group it checks that precondition
+// 2 * l * bound² <= order of the cyclic group holds for any group with
+// a modulus of the given bit length, and returns an error naming the
+// maximal supported boundBits otherwise. The error then wraps
+// fe.ErrBoundTooLarge.
+func NewDDHBits(l, modulusLength, boundBits int) (*DDH, error) {
+	if l < 1 {
+		return nil, fmt.Errorf("length of vectors should be positive")
+	}
+	if boundBits < 0 {
+		return nil, fmt.Errorf("bound bits should not be negative")
+	}
+	if max := maxBoundBits(l, modulusLength); boundBits > max {
+		if max < 0 {
+			return nil, fmt.Errorf("%w: no bound is supported for l = %d and modulus length %d",
+				fe.ErrBoundTooLarge, l, modulusLength)
+		}
+		return nil, fmt.Errorf("%w: bound of %d bits is not supported, at most %d bits are supported for l = %d and modulus length %d",
+			fe.ErrBoundTooLarge, boundBits, max, l, modulusLength)
+	}
+
+	return NewDDH(l, modulusLength, new(big.Int).Lsh(big.NewInt(1), uint(boundBits)))
+}
+
+// maxBoundBits returns the largest b such that the bound 2^b satisfies
+// 2 * l * 2^(2b) <= 2^(modulusLength - 2), the smallest possible order
+// of the group for a modulus of modulusLength bits. It returns a
+// negative value if there is no such b.
+func maxBoundBits(l, modulusLength int) int {
+	// 2 * l * 4^b <= 2^(m - 2) iff ceil(log2(l)) <= m - 3 - 2b
+	free := modulusLength - 3 - bits.Len(uint(l-1))
+	if free < 0 {
+		return -1
+	}
+
+	return free / 2
+}
+
+// NewDDHDeterministic configures a new instance of the scheme,
+// deriving all the parameters (P, Q and G) deterministically from
+// the provided seed. It accepts the length of input vectors l, the
+// bit length of the modulus (we are operating in the Z_p group),
+// a bound by which coordinates of input vectors are bounded, and
+// the seed. Calling it with the same arguments always yields the
+// same parameters, which allows independent parties to set up the
+// same group without exchanging it.
+//
+// Anyone knowing the seed can reproduce the parameters, thus the
+// seed must be kept secret if unpredictability of the parameters
+// matters.
+//
+// It returns an error in case the scheme could not be properly
+// configured, or if precondition 2 * l * bound² is > order of the cyclic
+// group, in which case the error wraps fe.ErrBoundTooLarge.
+func NewDDHDeterministic(l, modulusLength int, bound *big.Int, seed []byte) (*DDH, error) {
+	return newDDHFromReader(l, modulusLength, bound, internal.NewDetReader(seed))
+}
+
+// newDDHFromReader configures a new instance of the scheme, generating
+// the parameters with random as the only source of randomness.
+func newDDHFromReader(l, modulusLength int, bound *big.Int, random io.Reader) (*DDH, error) {
+	key, err := keygen.NewElGamalFromReader(modulusLength, random)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := internal.CheckOrderBound(l, bound, bound, key.Q); err != nil {
+		return nil, err
+	}
+
+	return &DDH{
+		Params: &DDHParams{
+			L:     l,
+			Bound: bound,
+			G:     key.G,
+			P:     key.P,
+			Q:     key.Q,
+		},
+	}, nil
+}
+
+// PrecompModulusLengths returns the modulus lengths for which
+// NewDDHPrecomp offers precomputed prime numbers and generators,
+// in increasing order.
+func PrecompModulusLengths() []int {
+	return keygen.PrecompModulusLengths()
+}
+
+// SupportsPrecomp returns true if NewDDHPrecomp can be used with
+// the modulus length n.
+func SupportsPrecomp(n int) bool {
+	for _, m := range PrecompModulusLengths() {
+		if m == n {
+			return true
+		}
+	}
+
+	return false
+}
+
 // NewDDHPrecomp configures a new instance of the scheme based on
 // precomputed prime numbers and generators.
 // It accepts the length of input vectors l, the bit length of the
@@ -88,58 +218,89 @@ func NewDDH(l, modulusLength int, bound *big.Int) (*DDH, error) {
 // be one of values 1024, 1536, 2048, 2560, 3072, or 4096.
 //
 // It returns an error in case the scheme could not be properly
-// configured, or if precondition l * bound² is >= order of the cyclic
-// group.
+// configured, or if precondition 2 * l * bound² is > order of the cyclic
+// group, in which case the error wraps fe.ErrBoundTooLarge.
 func NewDDHPrecomp(l, modulusLength int, bound *big.Int) (*DDH, error) {
-	zero := big.NewInt(0)
-	one := big.NewInt(1)
-	two := big.NewInt(2)
-
-	g := new(big.Int)
-	p := new(big.Int)
-
-	if modulusLength == 1024 {
-		g.SetString("34902160241479276675539633849372382885917193816560610471607073855548755350834003692485735908635894317735639518678334280193650806072183057417077181724192674928134805218882803812978345229222559213790765817899845072682155064387311523738581388872686127675360979304234957611566801734164757915959042140104663977828", 10)
-		p.SetString("166211269243229118758738154756726384542659478479960313411107431885216572625212662756677338184675400324411541201832214281445670912135683272416408753424543622705770319923251281963485084208425069817917631106045349238686234860629044433560424091289406000897029571960128048529362925472176997104870527051276406995203", 10)
-	} else if modulusLength == 1536 {
-		g.SetString("676416913692519694440150163403654362412279108516867264953779609011365998625435399420336578530015558254310139891236630566729665914687641028600402606957815727025192669238117788115237116562468680376464346714542467465836552396661693422160454402926392749202926871877212792118140354124110927269910674002861908621272286950597240072605316784317536178700101838123530590145680002962405974024190384775185108002307650499125333676880320808656556635493186351335151559453463208", 10)
-		p.SetString("1851297899986638926486011430658634631676522135433726749065856232802142091866650774719879427474637700607873256035038534449089405369134066444876856913629831069906506096279113968447116822488133963417347136141052507685108634240736100862550194947326287783557220764070479431781692630708747550712729778398000353165406458520850089303530985563143326919073190605085889925484113854496074216626577246143598303709289292397203458923541841135799203967503522114881404128535647507", 10)
-	} else if modulusLength == 2048 {
-		g.SetString("4006960929413042209594165215465319088439374252008797022450541422457034721098828647078778605657155669917104962611933792130890703423519992986737966991597160684973795472419962788730248050852176194215699504914899438223683843401963466624139534923052671383315398134823370041633710463630745156269175253639670460050105594663691338308037509280576148624454011047879615100156717631945194107791315234171086603775159708325087759679758438868772220133433497821899045165244202228696902434100209752952701657306825368599999359102329396520012735146260911352901326915877502873633420811221206110021993351144711002138373506576799781061829", 10)
-		p.SetString("28884237504713658990682089080899862128005980675308910325841161962760155725037929764087367167449843609136681034352509183117742758446654629096509285354423361556493020266963222508540306384896802796001914743293196010488452478370041404523014215612960481024232879327123268440037633547483165934132901270561772860319969916305482525766132307669097012989986613879246932730824899649301621408341438037745468033187743673001187803377254713546325789438300798311106106322698517805307792059495696632070953526611920926003483451787562399452650878943515646786958216714025307572678422373120397225912926110031401983688860264234966561627699", 10)
-	} else if modulusLength == 2560 {
-		g.SetString("283408881721750179985507845260248881237898607313021593637913985490973593382472548378053368228310040484438920416918021737085067966444840449068073874437662089659563355479608930182263107110969154912883370207053052795964658868443319273167870311282045348396320159912742394374241864712808383029954025256232806465551969466207671603658677963161975454703127476120201164519187150268352527923664649275471494757270139533433456630363925187498055211365480086561354743681517539297815712218419607006668655891574362066382949706266666189227897710299445185100212256741698216505337617571970963008519334554537811591236478130526432239803909461119767954934793813410765013072006162612226471775059215628326278458577643374735250370115470812597459244082296191871275203831471332697557979904062571849", 10)
-		p.SetString("403126381462544353337185732949672984701995200694926494258456907009253008321275627278199160008680481542251933923210212978304159426174307419863781623411302777276318286800690060166638633211627521530173324015527027650548199185539205697958056639406116068885574865579676651743820636201007864067569576455725489531113260031526605827601510665037511961715114944815619491261828558745083975042855063688267346905844510423020844412350570902289599734320004108557140241966071165594059732527795488131297017205383953304055105007982366596746708951250486384299368612656872813778220074826250625689603663742175288397398948456522281031888042417278385238985218731264092285591879578299600853004336936458454638992426900228708418575870946630137618851131144232868141478901063119847104013555395370887", 10)
-	} else if modulusLength == 3072 {
-		g.SetString("3696261717511684685041982088526264061294500298114921057816954458306445697150348237912729036967670872345042594238223317055749478029025374644864924550052402546275985983344583674703146236623453822520422465163020824494790581472736649085281450730460445260696087738043872307629635997875332076478424042345012004769107421873566499123042621978973433575500345010912635742477932006291250637245855027695943163956584173316781442078828050076620331751405548730676363847526959436516279320074682721438642683731766682502490935962962293815202487144775533102010333956118641968798500514719248831145108532912211817219793191951880318961073149276914867129023978524587935704313755469570162971499124682746476415187933097132047611840762510892175328320025164466873845777990557296853549970943298347924080102740724512079409979152285019931666423541870247789529268168448010024121369388707140296446100906359619586133848407970098685310317291828335700424602208", 10)
-		p.SetString("4387756306134544957818467663802660683665166110605728231080818705443663402154316615145921798856363268744945754470238000282108344905251127487705736550297997444150840902348669718478564904142834154197029830975532074167513046443903186309497214496864577129616824062991068960005865144004932069025136224356325248036029606434443391988386519658751798077031844645051726026696307027395796695909035405241040411794836124123435225690961994089776517262574417789067836840997650095451062948856617211542724543995145259735683916440579956961657374517806591607068842498749297993409884001044324428640569001916341503645559748760311343179943896427393009949062735145363544745972252566600994034655540841225414736222780096833045470605544717177880459300618917961703559234544541206877026518430276932498602360341258899345739335298856394124351357206871568254540730107127298623178526868418799471896060015463201459762913197633841160710893895836663035998106119", 10)
-	} else if modulusLength == 4096 {
-		g.SetString("51665588681810560577916524923861643358980285220048008212528567741884121491554604183472728540139463099618903178110360757930742372390027135064809646425064896539133721148335557788263239281487173350543811713890328584918216783142094297306639941000480756707312457878765754357205186485080839623690156744636468433787780205323460166423447602447200754978133176713947189000663528355089645281397174452923418212485422962705227706103188302892660448134233848971142570881089940852441776074246332915421265800026335300100610273942459340241610730244726628211914068945587128124478812632725838440727321816905181830592204023095726270782834020990986443265625389712733369116937470448592846480352222814297792606318850361699893703272484112273500581408730519942517586496563772194165844831300501908379990979449691597045730512107756238377635183257797115883839801779086058652272455400286891699445584526719648220045380141260347316315487340493029966105973850214850475440630205768783542021741101804842248602349004364816943429122368563644935802417389995380389429997320053299323220481603252879925927515844929958940305561718295197935926645561977544440676439150126025681320050786964708227836328341875446457912905977470123640014345655062829575775837287500880054558386787", 10)
-		p.SetString("1022249395832567838406986294560330159176972202126664245047364146720891252715766488477689126342364655087193411078517616569887825896401401223927363505007778278205623713273194552498760148834874746839752870298152746450585455651115247220867383465863156721401567161663838310658875672995951663020449772454232797368263754624173026584111779206080723120076751471597509403139249260220696195263597156452889920392585797464801375940661326779247976331028637271512085826066667631423502199894046717721786935806581428328491087482664043743281068318459302242239861275878019857365021173868449409246193470959347916848019032536247915451026158871684654213802886886213841729258073333569276986893577214659899227179735448593265633219968622571880602115519942763955551007919826002851866939641065270816032435114864853636918330698605282572789904941484540512478406984407320963402583009124880812235841866246441862987563989772424040933513333746472128494254253767426962063553015635240386636751473945937412527996558505231385625318878887383161350102080329744822052478052004574860361461762694379860797225344866320388590336321515376486033237159694567932935601775209663052272120524337888258857351777348841323194553467226791591208931619058871750498804369190487499494069660723", 10)
-	} else {
-		return nil, fmt.Errorf("modulus length should be one of values 1024, 1536, 2048, 2560, 3072, or 4096")
+	group, err := keygen.NewPrecompGroup(modulusLength)
+	if err != nil {
+		return nil, err
 	}
 
-	q := new(big.Int).Sub(p, one)
-	q.Div(q, two)
-
-	if new(big.Int).Mul(big.NewInt(int64(2*l)), new(big.Int).Exp(bound, two, zero)).Cmp(q) > 0 {
-		return nil, fmt.Errorf("2 * l * bound^2 should be smaller than group order")
+	if err := internal.CheckOrderBound(l, bound, bound, group.Q); err != nil {
+		return nil, err
 	}
 
 	sip := DDH{
 		Params: &DDHParams{
 			L:     l,
 			Bound: bound,
-			G:     g,
-			P:     p,
-			Q:     q,
+			G:     group.G,
+			P:     group.P,
+			Q:     group.Q,
 		},
 	}
 
 	return &sip, nil
 }
 
+// NewDDHForSecurityLevel configures a new instance of the scheme
+// based on the precomputed group providing the given NIST security
+// level in bits: 112 maps to a 2048-bit and 128 to a 3072-bit modulus,
+// following the table of SecurityBits. It accepts the length of input
+// vectors l, the security level and a bound by which coordinates of
+// input vectors are bounded.
+//
+// It returns an error if the level is not in the table or is below
+// 112 bits, if there is no precomputed group for the level (e.g. the
+// 7680-bit modulus of level 192), or if precondition
+// 2 * l * bound² is > order of the cyclic group.
+func NewDDHForSecurityLevel(l, secLevel int, bound *big.Int) (*DDH, error) {
+	modulusLength, err := keygen.ModulusLengthForSecurity(secLevel)
+	if err != nil {
+		return nil, err
+	}
+	if !SupportsPrecomp(modulusLength) {
+		return nil, fmt.Errorf("no precomputed group for security level %d, which needs a %d-bit modulus",
+			secLevel, modulusLength)
+	}
+
+	return NewDDHPrecomp(l, modulusLength, bound)
+}
+
+// NewDDHFromPrime configures a new instance of the scheme in the
+// Z_p group for a given safe prime p, deriving the order of the
+// group Q = (p - 1) / 2 and a generator G of the subgroup of order Q.
+// G is derived deterministically, so that any implementation can
+// reproduce it from p: it is b² mod p for the smallest integer b >= 2
+// such that b² mod p has order Q, which for a safe prime is G = 4.
+// It accepts the length of input vectors l, the modulus p and a bound
+// by which coordinates of input vectors are bounded.
+//
+// It returns an error if p is not a safe prime, i.e. if p or
+// (p - 1) / 2 is not a prime, or if precondition 2 * l * bound² is
+// > order of the cyclic group, in which case the error wraps
+// fe.ErrBoundTooLarge.
+func NewDDHFromPrime(l int, p, bound *big.Int) (*DDH, error) {
+	key, err := keygen.NewElGamalFromPrime(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := internal.CheckOrderBound(l, bound, bound, key.Q); err != nil {
+		return nil, err
+	}
+
+	return &DDH{
+		Params: &DDHParams{
+			L:     l,
+			Bound: bound,
+			G:     key.G,
+			P:     key.P,
+			Q:     key.Q,
+		},
+	}, nil
+}
+
 // NewDDHFromParams takes configuration parameters of an existing
 // DDH scheme instance, and reconstructs the scheme with same configuration
 // parameters. It returns a new DDH instance.
@@ -149,13 +310,114 @@ func NewDDHFromParams(params *DDHParams) *DDH {
 	}
 }
 
+// WithTighterBound returns a copy of the scheme instance with the
+// bound lowered to newBound. The copy shares the group and thus the
+// master keys, derived keys and ciphertexts of the original instance,
+// while its decryption searches for the discrete logarithm in a smaller
+// interval and is therefore faster.
+//
+// Ciphertexts produced by the original instance remain decryptable by
+// the copy only if the encrypted vectors and vectors y are actually
+// bounded by newBound; otherwise the inner product might fall out of
+// the searched interval and decryption fails. It returns an error if
+// newBound is not positive or is greater than the current bound.
+func (d *DDH) WithTighterBound(newBound *big.Int) (*DDH, error) {
+	if newBound.Sign() <= 0 {
+		return nil, fmt.Errorf("bound should be positive")
+	}
+	if newBound.Cmp(d.Params.Bound) > 0 {
+		return nil, fmt.Errorf("new bound should not be greater than the current bound")
+	}
+
+	params := *d.Params
+	params.Bound = new(big.Int).Set(newBound)
+	c := *d
+	c.Params = &params
+
+	return &c, nil
+}
+
+// WithBoundY returns a copy of the scheme instance in which the
+// coordinates of vectors y are bounded by boundY instead of the bound
+// of the scheme, which keeps bounding the coordinates of vectors x.
+// This allows large weights y on small data x, as long as the inner
+// products still fit the group.
+//
+// It returns an error if boundY is not positive, or if precondition
+// 2 * l * bound * boundY is > order of the cyclic group, in which case
+// the error wraps fe.ErrBoundTooLarge.
+func (d *DDH) WithBoundY(boundY *big.Int) (*DDH, error) {
+	if boundY.Sign() <= 0 {
+		return nil, fmt.Errorf("bound should be positive")
+	}
+	if err := internal.CheckOrderBound(d.Params.L, d.Params.Bound, boundY, d.Params.Q); err != nil {
+		return nil, err
+	}
+
+	params := *d.Params
+	params.BoundY = new(big.Int).Set(boundY)
+	c := *d
+	c.Params = &params
+
+	return &c, nil
+}
+
+// boundY returns the bound on the coordinates of vectors y.
+func (d *DDH) boundY() *big.Int {
+	if d.Params.BoundY != nil {
+		return d.Params.BoundY
+	}
+
+	return d.Params.Bound
+}
+
 // GenerateMasterKeys generates a pair of master secret key and master
-// public key for the scheme. It returns an error in case master keys
+// public key for the scheme. The coordinates are generated in parallel
+// by a pool of runtime.GOMAXPROCS(0) workers, each sampling with its
+// own sampler, unless a source of randomness was set with WithRand, in
+// which case they are generated sequentially, so that the keys only
+// depend on the stream read. It returns an error in case master keys
 // could not be generated.
 func (d *DDH) GenerateMasterKeys() (data.Vector, data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+	if d.random != nil {
+		return d.generateMasterKeys(d.uniformSampler(big.NewInt(2), d.Params.Q))
+	}
+
+	masterSecKey := make(data.Vector, d.Params.L)
+	masterPubKey := make(data.Vector, d.Params.L)
+	err := internal.ParallelRanges(d.Params.L, func(lo, hi int) error {
+		sampler := sample.NewUniformRange(big.NewInt(2), d.Params.Q)
+		for i := lo; i < hi; i++ {
+			x, err := sampler.Sample()
+			if err != nil {
+				return err
+			}
+			masterSecKey[i] = x
+			masterPubKey[i] = internal.ModExp(d.Params.G, x, d.Params.P)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return masterSecKey, masterPubKey, nil
+}
+
+// generateMasterKeys generates a pair of master secret key and master
+// public key, sampling the master secret key with the provided sampler.
+// The coordinates are generated sequentially, so that a sampler
+// reading a fixed stream always yields the same keys.
+func (d *DDH) generateMasterKeys(sampler sample.Sampler) (data.Vector, data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+
 	masterSecKey := make(data.Vector, d.Params.L)
 	masterPubKey := make(data.Vector, d.Params.L)
-	sampler := sample.NewUniformRange(big.NewInt(2), d.Params.Q)
 
 	for i := 0; i < d.Params.L; i++ {
 		x, err := sampler.Sample()
@@ -174,74 +436,348 @@ func (d *DDH) GenerateMasterKeys() (data.Vector, data.Vector, error) {
 // functional encryption key. In case the key could not be derived, it
 // returns an error.
 func (d *DDH) DeriveKey(masterSecKey, y data.Vector) (*big.Int, error) {
-	if err := y.CheckBound(d.Params.Bound); err != nil {
+	if err := d.checkOpen(); err != nil {
 		return nil, err
 	}
-
-	key, err := masterSecKey.Dot(y)
-	if err != nil {
+	if err := y.CheckBound(d.boundY()); err != nil {
 		return nil, err
 	}
-	return new(big.Int).Mod(key, d.Params.Q), nil
+
+	return internal.DotMod(masterSecKey, y, d.Params.Q)
+}
+
+// ValidateInput checks whether the input vector x can be encrypted by
+// the scheme, i.e. whether it is of length l and its coordinates are
+// bounded by the bound of the scheme, without sampling randomness or
+// computing any exponentiation. It returns an error describing the
+// first problem found, a *data.BoundViolationError if x is not
+// bounded.
+func (d *DDH) ValidateInput(x data.Vector) error {
+	if err := d.checkOpen(); err != nil {
+		return err
+	}
+	if len(x) != d.Params.L {
+		return fmt.Errorf("input vector should be of length %d, got %d", d.Params.L, len(x))
+	}
+
+	return x.CheckBound(d.Params.Bound)
 }
 
 // Encrypt encrypts input vector x with the provided master public key.
 // It returns a ciphertext vector. If encryption failed, error is returned.
-func (d *DDH) Encrypt(x, masterPubKey data.Vector) (data.Vector, error) {
+// The encryption can be configured with options, see EncryptOption.
+// The master public key and x must be of length l; otherwise the error
+// wraps the error of a malformed public key or input, respectively.
+func (d *DDH) Encrypt(x, masterPubKey data.Vector, opts ...EncryptOption) (data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckEncryptLengths(x, masterPubKey, d.Params.L); err != nil {
+		return nil, err
+	}
 	if err := x.CheckBound(d.Params.Bound); err != nil {
 		return nil, err
 	}
 
-	sampler := sample.NewUniformRange(big.NewInt(2), d.Params.Q)
-	r, err := sampler.Sample()
+	// the powers of g are computed together, sharing the precomputation
+	gx := internal.ModExpSlice(d.Params.G, x, d.Params.P)
+
+	return d.encryptWith(d.newEncryptConfig(opts), masterPubKey, func(i int) *big.Int {
+		return gx[i]
+	})
+}
+
+// encryptWith samples the randomness r of an encryption configured by
+// config and returns the ciphertext of x with r, as encryptWithNonce
+// does.
+func (d *DDH) encryptWith(config *encryptConfig, masterPubKey data.Vector, gx func(i int) *big.Int) (data.Vector, error) {
+	r, err := d.sampleNonce(config)
 	if err != nil {
 		return nil, err
 	}
 
-	ciphertext := make([]*big.Int, len(x)+1)
-	// ct0 = g^r
-	ct0 := new(big.Int).Exp(d.Params.G, r, d.Params.P)
-	ciphertext[0] = ct0
+	return d.encryptWithNonce(config, r, masterPubKey, nil, gx), nil
+}
 
-	for i := 0; i < len(x); i++ {
-		// ct_i = h_i^r * g^x_i
+// sampleNonce samples the randomness r of an encryption with the
+// sampler of config, and checks it with the nonce guard of the scheme
+// unless r is derived deterministically.
+func (d *DDH) sampleNonce(config *encryptConfig) (*big.Int, error) {
+	r, err := config.sampler.Sample()
+	if err != nil {
+		return nil, err
+	}
+	if d.nonceGuard != nil && !config.deterministic {
+		if err := d.nonceGuard.check(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// encryptWithNonce returns the ciphertext (g^r, mpk_1^r * g^x_1, ...,
+// mpk_l^r * g^x_l) of x with randomness r, where gx(i) returns g^x_i,
+// or nil if x_i = 0. If zero is not nil, it holds the encryption
+// (g^r, mpk_1^r, ..., mpk_l^r) of the zero vector with r, whose
+// components are used instead of being computed again, e.g. for the
+// ciphertexts of a batch sharing r. The progress is reported to the
+// callback of config, if one was set.
+func (d *DDH) encryptWithNonce(config *encryptConfig, r *big.Int, masterPubKey, zero data.Vector, gx func(i int) *big.Int) data.Vector {
+	l := len(masterPubKey)
+	ciphertext := make(data.Vector, l+1)
+	// ct0 = g^r
+	if zero != nil {
+		ciphertext[0] = new(big.Int).Set(zero[0])
+	} else {
+		ciphertext[0] = new(big.Int).Exp(d.Params.G, r, d.Params.P)
+	}
+	for i, h := range masterPubKey {
 		// ct_i = mpk[i]^r * g^x_i
-		t1 := new(big.Int).Exp(masterPubKey[i], r, d.Params.P)
-		t2 := internal.ModExp(d.Params.G, x[i], d.Params.P)
-		ct := new(big.Int).Mod(new(big.Int).Mul(t1, t2), d.Params.P)
+		ct := new(big.Int)
+		if zero != nil {
+			ct.Set(zero[i+1])
+		} else {
+			ct.Exp(h, r, d.Params.P)
+		}
+		if p := gx(i); p != nil {
+			ct.Mod(ct.Mul(ct, p), d.Params.P)
+		}
 		ciphertext[i+1] = ct
+		if config.progress != nil && ((i+1)%config.progressEvery == 0 || i+1 == l) {
+			config.progress(float64(i+1) / float64(l))
+		}
 	}
 
-	return ciphertext, nil
+	return ciphertext
+}
+
+// CiphertextSize returns the size in bytes of a ciphertext of the
+// scheme, i.e. of (L+1) group elements, when serialized with
+// data.MarshalVectors. The actual size is smaller in the rare case
+// when all the components are shorter than the modulus P.
+func (d *DDH) CiphertextSize() int {
+	return data.MarshalVectorsSize(1, d.Params.L+1, (d.Params.P.BitLen()+7)/8)
 }
 
 // Decrypt accepts the encrypted vector, functional encryption key, and
 // a plaintext vector y. It returns the inner product of x and y.
 // If decryption failed, error is returned.
+// A ciphertext that does not have exactly l + 1 components, or y that is
+// not of length l, is rejected before it is used, with an error wrapping
+// the error of a malformed ciphertext or input that states the expected
+// and the actual length.
 func (d *DDH) Decrypt(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
-	if err := y.CheckBound(d.Params.Bound); err != nil {
+	if d.results != nil {
+		return d.decryptCached(cipher, key, y)
+	}
+
+	r, err := d.innerProdElement(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.solveDlog(r)
+}
+
+// DecryptSigned works like Decrypt and makes its handling of signs
+// explicit: x and y may have negative coordinates, and the inner
+// product is searched in the whole signed range [-l * bound * boundY,
+// l * bound * boundY], where boundY is the bound on y (Bound unless set
+// with WithBoundY). Every inner product of bounded x and y lies in the
+// range and is recovered with its sign. It returns an error if
+// decryption failed or the result falls out of the range, which a
+// custom solver might return.
+func (d *DDH) DecryptSigned(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	res, err := d.Decrypt(cipher, key, y)
+	if err != nil {
 		return nil, err
 	}
+	if new(big.Int).Abs(res).Cmp(d.dlogBound()) > 0 {
+		return nil, fmt.Errorf("inner product %s is out of the signed range of the scheme", res)
+	}
 
-	num := big.NewInt(1)
-	for i, ct := range cipher[1:] {
-		t1 := internal.ModExp(ct, y[i], d.Params.P)
-		num = num.Mod(new(big.Int).Mul(num, t1), d.Params.P)
+	return res, nil
+}
+
+// DecryptInt64 works like Decrypt, but returns the inner product as
+// an int64. It returns an error wrapping fe.ErrOverflow if the inner
+// product does not fit into an int64, which is possible only if
+// l * bound * boundY exceeds the largest int64, and an error if
+// decryption failed.
+func (d *DDH) DecryptInt64(cipher data.Vector, key *big.Int, y data.Vector) (int64, error) {
+	res, err := d.Decrypt(cipher, key, y)
+	if err != nil {
+		return 0, err
 	}
+	if !res.IsInt64() {
+		return 0, fmt.Errorf("%w: inner product %s does not fit into int64", fe.ErrOverflow, res)
+	}
+
+	return res.Int64(), nil
+}
 
-	denom := internal.ModExp(cipher[0], key, d.Params.P)
-	denomInv := new(big.Int).ModInverse(denom, d.Params.P)
-	r := new(big.Int).Mod(new(big.Int).Mul(num, denomInv), d.Params.P)
+// decryptCached works like Decrypt, but it first looks the result up
+// in the result cache of the scheme, and caches it after a successful
+// decryption. The inputs are checked also on a cache hit.
+func (d *DDH) decryptCached(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	if err := d.checkDecryptInput(cipher, y); err != nil {
+		return nil, err
+	}
 
-	bound := new(big.Int).Mul(big.NewInt(int64(d.Params.L)), new(big.Int).Exp(d.Params.Bound, big.NewInt(2), big.NewInt(0)))
+	k := resultCacheKey(cipher, key, y)
+	if res, ok := d.results.get(k); ok {
+		return res, nil
+	}
 
-	calc, err := dlog.NewCalc().InZp(d.Params.P, d.Params.Q)
+	r, err := d.innerProdElement(cipher, key, y)
 	if err != nil {
 		return nil, err
 	}
-	calc = calc.WithNeg()
+	res, err := d.solveDlog(r)
+	if err != nil {
+		return nil, err
+	}
+	d.results.put(k, res)
+
+	return res, nil
+}
+
+// DecryptUnbounded works like Decrypt, but it is able to recover
+// the inner product of x and y also when it exceeds the bound
+// l * bound² implied by the parameters of the scheme, e.g. when the
+// bound was set too conservatively. The discrete logarithm is searched
+// for with the solver of the scheme in successive windows of the
+// width of the formal bound, see dlog.SolveWindowed, thus the time
+// needed grows linearly with the size of the result.
+// The search gives up with an error once the absolute value of the
+// result would exceed max.
+func (d *DDH) DecryptUnbounded(cipher data.Vector, key *big.Int, y data.Vector, max *big.Int) (*big.Int, error) {
+	r, err := d.innerProdElement(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return dlog.SolveWindowed(d.dlogSolver(), r, d.Params.G, d.Params.P, d.Params.Q, d.dlogBound(), max)
+}
+
+// innerProdElement checks the inputs of decryption and returns
+// g^<x,y>, the inner product of x and y in the exponent.
+func (d *DDH) innerProdElement(cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	if err := d.checkDecryptInput(cipher, y); err != nil {
+		return nil, err
+	}
+
+	// r = prod_i ct_i^y_i / ct_0^key, computed with a single
+	// modular inversion
+	bases := append(data.Vector{cipher[0]}, cipher[1:]...)
+	exps := append(data.Vector{new(big.Int).Neg(key)}, y...)
+
+	return internal.ModExpProduct(bases, exps, d.Params.P), nil
+}
+
+// CheckCiphertextShape checks that cipher has the shape of a
+// ciphertext of the scheme: l + 1 components, each in [1, P). It is a
+// cheap precondition for rejecting malformed ciphertexts before
+// decryption; it does not verify that the components belong to the
+// subgroup generated by G. It returns an error describing the first
+// violation.
+func (d *DDH) CheckCiphertextShape(cipher data.Vector) error {
+	return internal.CheckCiphertextShape(cipher, d.Params.L+1, d.Params.P)
+}
+
+// checkDecryptInput checks that the ciphertext has l + 1 components
+// and that y is of length l and bounded.
+func (d *DDH) checkDecryptInput(cipher data.Vector, y data.Vector) error {
+	if err := d.checkOpen(); err != nil {
+		return err
+	}
+	if err := internal.CheckCiphertextLength(cipher, d.Params.L+1); err != nil {
+		return err
+	}
+	if err := internal.CheckInputLength(y, d.Params.L); err != nil {
+		return err
+	}
+
+	return y.CheckBound(d.boundY())
+}
+
+// solveDlog returns the discrete logarithm of h with respect to G,
+// searched within [-l * bound * boundY, l * bound * boundY] by the solver of the
+// scheme.
+func (d *DDH) solveDlog(h *big.Int) (*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	return d.dlogSolver().Solve(h, d.Params.G, d.Params.P, d.Params.Q, d.dlogBound())
+}
+
+// dlogBound returns the maximal absolute value l * bound * boundY of the
+// inner product.
+func (d *DDH) dlogBound() *big.Int {
+	bound := new(big.Int).Mul(d.Params.Bound, d.boundY())
+	bound.Mul(bound, big.NewInt(int64(d.Params.L)))
+	return bound
+}
+
+// dlogSolver returns the solver of the discrete logarithm of the
+// scheme, dlog.BabyStepGiantStepSolver if none was set.
+func (d *DDH) dlogSolver() dlog.Solver {
+	if d.solver == nil {
+		return dlog.BabyStepGiantStepSolver{}
+	}
+
+	return d.solver
+}
+
+// SecurityBits estimates the security level of the parameters of the
+// scheme in bits from the bit length of the modulus P, following NIST
+// SP 800-57: 1024 bits give 80, 2048 bits 112, 3072 bits 128, 7680
+// bits 192 and 15360 bits 256 bits of security, and a modulus shorter
+// than 1024 bits gives 0. The level is limited by half the bit length
+// of the group order Q. See also CheckSecurity.
+func (d *DDH) SecurityBits() int {
+	return keygen.SecurityBits(d.Params.P, d.Params.Q)
+}
+
+// CheckSecurity returns an error wrapping fe.ErrWeakParams if the
+// parameters of the scheme provide less than 112 bits of security,
+// as estimated by SecurityBits.
+func (d *DDH) CheckSecurity() error {
+	if bits := d.SecurityBits(); bits < keygen.MinSecurityBits {
+		return fmt.Errorf("%w: estimated %d bits for a %d-bit modulus", fe.ErrWeakParams, bits, d.Params.P.BitLen())
+	}
 
-	res, err := calc.WithBound(bound).BabyStepGiantStep(r, d.Params.G)
+	return nil
+}
+
+// DecryptDeadline works like Decrypt, but it gives up the search for
+// the inner product once the deadline passes, returning a
+// *dlog.TimeoutError holding how far the search got. The inner product
+// is searched with dlog.SolveDeadline regardless of the solver set
+// for the scheme.
+func (d *DDH) DecryptDeadline(cipher data.Vector, key *big.Int, y data.Vector, deadline time.Time) (*big.Int, error) {
+	r, err := d.innerProdElement(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return dlog.SolveDeadline(r, d.Params.G, d.Params.P, d.dlogBound(), deadline)
+}
+
+// DecryptWithContext works like Decrypt, but it stops the search for
+// the inner product once ctx is done, returning ctx.Err(). The context
+// is checked before decryption starts and regularly during the search.
+// The inner product is searched with dlog.SolveContext regardless of
+// the solver set for the scheme.
+func (d *DDH) DecryptWithContext(ctx context.Context, cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r, err := d.innerProdElement(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
 
-	return res, err
+	return dlog.SolveContext(ctx, r, d.Params.G, d.Params.P, d.dlogBound())
 }
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// DDHAffineKey is a functional encryption key for the affine function
+// f(x) = <x, y> + C of the DDH scheme. C is public and known to the
+// decryptor.
+type DDHAffineKey struct {
+	Key *big.Int
+	C   *big.Int
+}
+
+// DeriveAffineKey takes master secret key, input vector y and a
+// constant c, and returns the functional encryption key for the affine
+// function f(x) = <x, y> + c. In case the key could not be derived, it
+// returns an error.
+func (d *DDH) DeriveAffineKey(masterSecKey, y data.Vector, c *big.Int) (*DDHAffineKey, error) {
+	if c == nil {
+		return nil, fmt.Errorf("constant should not be nil")
+	}
+	key, err := d.DeriveKey(masterSecKey, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DDHAffineKey{
+		Key: key,
+		C:   new(big.Int).Set(c),
+	}, nil
+}
+
+// DecryptAffine accepts the encrypted vector, functional encryption
+// key for an affine function and the vector y, and returns
+// <x, y> + C. The discrete logarithm is searched only for <x, y>, in
+// the same range as with Decrypt, and C is added to the result, thus
+// the decryptable values are the ones in [-l * bound * boundY + C,
+// l * bound * boundY + C], regardless of the size of C. In case
+// decryption failed, an error is returned.
+func (d *DDH) DecryptAffine(cipher data.Vector, key *DDHAffineKey, y data.Vector) (*big.Int, error) {
+	if key == nil || key.Key == nil || key.C == nil {
+		return nil, fmt.Errorf("affine key should have a key and a constant")
+	}
+	xy, err := d.Decrypt(cipher, key.Key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return xy.Add(xy, key.C), nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHAffine(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(100), big.NewInt(-100)})
+	y := data.NewVector([]*big.Int{big.NewInt(-100), big.NewInt(100)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// <x, y> = -20000 is at the edge of the search range, and c
+	// shifts the result beyond it
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	for _, c := range []*big.Int{big.NewInt(0), big.NewInt(7), big.NewInt(-7), big.NewInt(50000), new(big.Int).Neg(huge)} {
+		key, err := ddh.DeriveAffineKey(masterSecKey, y, c)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		res, err := ddh.DecryptAffine(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		expected := new(big.Int).Add(big.NewInt(-20000), c)
+		assert.Equal(t, 0, res.Cmp(expected), "obtained incorrect result for c = %s", c)
+	}
+
+	_, err = ddh.DeriveAffineKey(masterSecKey, y, nil)
+	assert.Error(t, err, "nil constant should be rejected")
+	_, err = ddh.DecryptAffine(cipher, &simple.DDHAffineKey{Key: big.NewInt(1)}, y)
+	assert.Error(t, err, "key without a constant should be rejected")
+}
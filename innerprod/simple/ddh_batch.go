@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// WithSharedRandomness makes EncryptBatch encrypt all the vectors of
+// the batch with the same randomness r, so that the terms mpk_i^r are
+// computed only once for the whole batch. It has no effect on Encrypt.
+//
+// This is insecure in general: the ciphertexts of x and x' share the
+// component g^r, and the quotient of their i-th components is
+// g^(x_i - x'_i), from which the difference of the plaintexts can be
+// recovered since the coordinates are bounded. It should only be used
+// in protocols where the plaintexts of a batch are known to be related,
+// e.g. re-randomizing the same vector, or where their differences are
+// public anyway.
+func WithSharedRandomness() EncryptOption {
+	return func(c *encryptConfig) {
+		c.sharedRandomness = true
+	}
+}
+
+// EncryptBatch encrypts each of the input vectors xs with the provided
+// master public key as Encrypt does, with the same options. Unless
+// WithSharedRandomness is among the options, every vector is encrypted
+// with independently sampled randomness. With it, a single r is sampled
+// for the whole batch, and the terms mpk_i^r are reused across the
+// vectors, leaving only the powers of g to be computed for each of them.
+//
+// The master public key and all the vectors must be of length l, as
+// for Encrypt. It returns an error if any of them is not of the proper
+// length, if any of the vectors is not bounded, or if encryption
+// failed.
+func (d *DDH) EncryptBatch(xs []data.Vector, masterPubKey data.Vector, opts ...EncryptOption) ([]data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	for _, x := range xs {
+		if err := internal.CheckEncryptLengths(x, masterPubKey, d.Params.L); err != nil {
+			return nil, err
+		}
+		if err := x.CheckBound(d.Params.Bound); err != nil {
+			return nil, err
+		}
+	}
+
+	config := d.newEncryptConfig(opts)
+	if !config.sharedRandomness {
+		ciphers := make([]data.Vector, len(xs))
+		for i, x := range xs {
+			cipher, err := d.Encrypt(x, masterPubKey, opts...)
+			if err != nil {
+				return nil, err
+			}
+			ciphers[i] = cipher
+		}
+
+		return ciphers, nil
+	}
+
+	r, err := d.sampleNonce(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// g^r and mpk_i^r, shared by all the ciphertexts
+	zero := d.encryptWithNonce(&encryptConfig{}, r, masterPubKey, nil, func(int) *big.Int {
+		return nil
+	})
+
+	ciphers := make([]data.Vector, len(xs))
+	for j, x := range xs {
+		gx := internal.ModExpSlice(d.Params.G, x, d.Params.P)
+		ciphers[j] = d.encryptWithNonce(config, r, masterPubKey, zero, func(i int) *big.Int {
+			return gx[i]
+		})
+	}
+
+	return ciphers, nil
+}
+
+// DecryptBatch decrypts each of the ciphertexts with the same
+// functional encryption key and plaintext vector y as Decrypt does,
+// returning the inner products in the order of the ciphertexts. The
+// ciphertexts are decrypted concurrently, by runtime.GOMAXPROCS(0)
+// goroutines, sharing a single baby-step table, which is built once
+// for the batch unless a solver or a table cache was set for the
+// scheme. The denominator ct_0^key is computed once for consecutive
+// ciphertexts with the same ct_0, e.g. those of EncryptBatch with
+// WithSharedRandomness. The results cached with WithResultCache are
+// not used.
+//
+// It stops with an error at the first ciphertext, in the order of the
+// input, that cannot be decrypted, e.g. one that does not pass
+// CheckCiphertextShape, and returns no results then. It also returns
+// an error if y is not bounded.
+func (d *DDH) DecryptBatch(ciphers []data.Vector, key *big.Int, y data.Vector) ([]*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckInputLength(y, d.Params.L); err != nil {
+		return nil, err
+	}
+	if err := y.CheckBound(d.boundY()); err != nil {
+		return nil, err
+	}
+	if len(ciphers) == 0 {
+		return []*big.Int{}, nil
+	}
+
+	solver := d.batchSolver()
+	bound := d.dlogBound()
+	res := make([]*big.Int, len(ciphers))
+	// the smallest index of a ciphertext that failed; the ciphertexts
+	// after it need not be decrypted
+	failed := int64(len(ciphers))
+	err := internal.ParallelRanges(len(ciphers), func(lo, hi int) error {
+		// ct_0 and (ct_0^key)^-1 of the previous ciphertext
+		var ct0, denInv *big.Int
+		for i := lo; i < hi; i++ {
+			if int64(i) > atomic.LoadInt64(&failed) {
+				return nil
+			}
+			cipher := ciphers[i]
+			err := d.CheckCiphertextShape(cipher)
+			if err == nil && (ct0 == nil || ct0.Cmp(cipher[0]) != 0) {
+				ct0 = cipher[0]
+				denInv = new(big.Int).Exp(ct0, key, d.Params.P)
+				if denInv.ModInverse(denInv, d.Params.P) == nil {
+					ct0 = nil
+					err = internal.ErrMalformedCipher
+				}
+			}
+			if err == nil {
+				// r = prod_i ct_i^y_i / ct_0^key
+				r := internal.ModExpProduct(cipher[1:], y, d.Params.P)
+				r.Mod(r.Mul(r, denInv), d.Params.P)
+				res[i], err = solver.Solve(r, d.Params.G, d.Params.P, d.Params.Q, bound)
+			}
+			if err != nil {
+				for f := atomic.LoadInt64(&failed); int64(i) < f; f = atomic.LoadInt64(&failed) {
+					if atomic.CompareAndSwapInt64(&failed, f, int64(i)) {
+						break
+					}
+				}
+				return fmt.Errorf("ciphertext %d: %w", i, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// batchSolver returns the solver of the discrete logarithm for
+// DecryptBatch: the solver of the scheme if one was set, and otherwise
+// a baby-step table for the whole range of inner products, or the
+// default solver if the range is too large for a table.
+func (d *DDH) batchSolver() dlog.Solver {
+	if d.solver != nil {
+		return d.solver
+	}
+	table, err := dlog.NewTable(d.Params.G, d.Params.P, d.dlogBound())
+	if err != nil {
+		return dlog.BabyStepGiantStepSolver{}
+	}
+
+	return table
+}
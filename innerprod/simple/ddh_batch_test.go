@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptBatch(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xs := []data.Vector{
+		data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)}),
+		data.NewVector([]*big.Int{big.NewInt(0), big.NewInt(5), big.NewInt(-1)}),
+	}
+	expected := []int64{-333, 13}
+
+	for _, opts := range [][]simple.EncryptOption{nil, {simple.WithSharedRandomness()}} {
+		ciphers, err := ddh.EncryptBatch(xs, masterPubKey, opts...)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		assert.Equal(t, len(xs), len(ciphers))
+		for i, cipher := range ciphers {
+			xy, err := ddh.Decrypt(cipher, key, y)
+			if err != nil {
+				t.Fatalf("Error during decryption: %v", err)
+			}
+			assert.Equal(t, 0, xy.Cmp(big.NewInt(expected[i])), "obtained incorrect inner product")
+		}
+		shared := ciphers[0][0].Cmp(ciphers[1][0]) == 0
+		assert.Equal(t, opts != nil, shared, "randomness should be shared only with the option")
+	}
+
+	// shared randomness gives the same ciphertexts as Encrypt with the same r
+	sampler := &fixedSampler{value: big.NewInt(12345)}
+	ciphers, err := ddh.EncryptBatch(xs, masterPubKey, simple.WithSampler(sampler), simple.WithSharedRandomness())
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	for i, x := range xs {
+		cipher, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler))
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		assert.Equal(t, cipher, ciphers[i])
+	}
+
+	_, err = ddh.EncryptBatch([]data.Vector{xs[0][:2]}, masterPubKey, simple.WithSharedRandomness())
+	assert.True(t, errors.Is(err, internal.ErrMalformedInput), "vector of wrong length should be rejected")
+	assert.Contains(t, err.Error(), "input vector length 2 does not match scheme length 3")
+	// the lengths are checked against the scheme, not only against each other
+	for _, opts := range [][]simple.EncryptOption{nil, {simple.WithSharedRandomness()}} {
+		_, err = ddh.EncryptBatch([]data.Vector{xs[0][:2]}, masterPubKey[:2], opts...)
+		assert.True(t, errors.Is(err, internal.ErrMalformedPubKey), "public key of wrong length should be rejected")
+	}
+	_, err = ddh.EncryptBatch([]data.Vector{xs[0], data.NewConstantVector(3, big.NewInt(101))}, masterPubKey)
+	assert.Error(t, err, "unbounded vector should be rejected")
+}
+
+func TestSimple_DDHDecryptBatch(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	n := 10
+	xs := make([]data.Vector, n)
+	for i := range xs {
+		xs[i] = data.NewConstantVector(3, big.NewInt(int64(i*10-50)))
+	}
+	ciphers, err := ddh.EncryptBatch(xs, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	// ciphertexts sharing ct_0 reuse the denominator
+	shared, err := ddh.EncryptBatch(xs, masterPubKey, simple.WithSharedRandomness())
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	ciphers = append(ciphers, shared...)
+	xs = append(xs, xs...)
+
+	for _, scheme := range []*simple.DDH{ddh, ddh.WithTableCache()} {
+		res, err := scheme.DecryptBatch(ciphers, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, len(ciphers), len(res))
+		for i, x := range xs {
+			xy, _ := x.Dot(y)
+			assert.Equal(t, 0, res[i].Cmp(xy), "obtained incorrect inner product %d", i)
+		}
+	}
+
+	res, err := ddh.DecryptBatch(nil, key, y)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(res))
+
+	// the first malformed ciphertext is reported
+	malformed := append([]data.Vector{}, ciphers...)
+	malformed[12] = malformed[12][:2]
+	malformed[15] = data.NewConstantVector(4, big.NewInt(0))
+	_, err = ddh.DecryptBatch(malformed, key, y)
+	assert.True(t, errors.Is(err, internal.ErrMalformedCipher), "malformed ciphertext should be reported")
+	assert.Contains(t, err.Error(), "ciphertext 12")
+
+	// a zero component other than ct_0 is reported as well, for every
+	// solver
+	for _, scheme := range []*simple.DDH{ddh, ddh.WithTableCache()} {
+		malformed = append([]data.Vector{}, ciphers...)
+		malformed[3] = append(data.Vector{}, malformed[3]...)
+		malformed[3][2] = big.NewInt(0)
+		_, err = scheme.DecryptBatch(malformed, key, y)
+		assert.True(t, errors.Is(err, internal.ErrMalformedCipher), "malformed ciphertext should be reported")
+		assert.Contains(t, err.Error(), "ciphertext 3")
+	}
+
+	_, err = ddh.DecryptBatch(ciphers, key, data.NewConstantVector(3, big.NewInt(101)))
+	assert.Error(t, err, "unbounded y should be rejected")
+}
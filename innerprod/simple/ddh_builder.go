@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// DDHBuilder configures a new instance of the DDH scheme step by step,
+// as an alternative to the constructors when several options are
+// combined. Its methods modify and return the builder, so that they
+// can be chained, e.g.
+//
+//	ddh, err := NewDDHBuilder(l).WithPrecomp(2048).WithBound(bound).WithResultCache(100).Build()
+//
+// A builder should not be used concurrently.
+type DDHBuilder struct {
+	l             int
+	modulusLength int
+	precomp       bool
+	bound         *big.Int
+	random        io.Reader
+	cacheSize     int
+}
+
+// NewDDHBuilder returns a builder of a scheme for input vectors of
+// length l.
+func NewDDHBuilder(l int) *DDHBuilder {
+	return &DDHBuilder{l: l}
+}
+
+// WithModulusLength sets the bit length of the modulus of a group that
+// is generated by Build.
+func (b *DDHBuilder) WithModulusLength(modulusLength int) *DDHBuilder {
+	b.modulusLength = modulusLength
+	b.precomp = false
+
+	return b
+}
+
+// WithPrecomp sets the bit length of the modulus of a precomputed
+// group used by Build, see NewDDHPrecomp.
+func (b *DDHBuilder) WithPrecomp(modulusLength int) *DDHBuilder {
+	b.modulusLength = modulusLength
+	b.precomp = true
+
+	return b
+}
+
+// WithBound sets the bound by which coordinates of input vectors are
+// bounded.
+func (b *DDHBuilder) WithBound(bound *big.Int) *DDHBuilder {
+	b.bound = bound
+
+	return b
+}
+
+// WithRand sets the source of randomness for generating the group,
+// crypto/rand by default. With a reader returning a fixed stream of
+// bytes the group is reproducible, see NewDDHDeterministic.
+func (b *DDHBuilder) WithRand(random io.Reader) *DDHBuilder {
+	b.random = random
+
+	return b
+}
+
+// WithResultCache sets the size of the cache of decrypted inner
+// products, see DDH.WithResultCache.
+func (b *DDHBuilder) WithResultCache(size int) *DDHBuilder {
+	b.cacheSize = size
+
+	return b
+}
+
+// Build configures the scheme. It returns an error if the modulus
+// length or the bound was not set, if randomness was set together
+// with a precomputed group, which needs none, or if the scheme could
+// not be configured, as with NewDDH and NewDDHPrecomp.
+func (b *DDHBuilder) Build() (*DDH, error) {
+	if b.modulusLength == 0 {
+		return nil, fmt.Errorf("modulus length should be set")
+	}
+	if b.bound == nil {
+		return nil, fmt.Errorf("bound should be set")
+	}
+
+	var d *DDH
+	var err error
+	switch {
+	case b.precomp && b.random != nil:
+		return nil, fmt.Errorf("randomness is not used with a precomputed group")
+	case b.precomp:
+		d, err = NewDDHPrecomp(b.l, b.modulusLength, b.bound)
+	case b.random != nil:
+		d, err = newDDHFromReader(b.l, b.modulusLength, b.bound, b.random)
+	default:
+		d, err = NewDDH(b.l, b.modulusLength, b.bound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.cacheSize > 0 {
+		d = d.WithResultCache(b.cacheSize)
+	}
+
+	return d, nil
+}
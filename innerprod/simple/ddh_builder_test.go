@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHBuilder(t *testing.T) {
+	bound := big.NewInt(100)
+	ddh, err := simple.NewDDHBuilder(3).WithPrecomp(1024).WithBound(bound).WithResultCache(10).Build()
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	precomp, err := simple.NewDDHPrecomp(3, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, precomp.Params, ddh.Params)
+
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		xy, err := ddh.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+	}
+}
+
+func TestSimple_DDHBuilderWithRand(t *testing.T) {
+	bound := big.NewInt(10)
+	seed := []byte("builder")
+	ddh, err := simple.NewDDHBuilder(2).WithModulusLength(128).WithBound(bound).
+		WithRand(internal.NewDetReader(seed)).Build()
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	deterministic, err := simple.NewDDHDeterministic(2, 128, bound, seed)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, deterministic.Params, ddh.Params)
+}
+
+func TestSimple_DDHBuilderInvalid(t *testing.T) {
+	bound := big.NewInt(10)
+	for name, b := range map[string]*simple.DDHBuilder{
+		"no modulus":      simple.NewDDHBuilder(2).WithBound(bound),
+		"no bound":        simple.NewDDHBuilder(2).WithPrecomp(1024),
+		"precomp rand":    simple.NewDDHBuilder(2).WithPrecomp(1024).WithBound(bound).WithRand(internal.NewDetReader(nil)),
+		"no precomp":      simple.NewDDHBuilder(2).WithPrecomp(1000).WithBound(bound),
+		"bound too large": simple.NewDDHBuilder(2).WithPrecomp(1024).WithBound(new(big.Int).Lsh(bound, 600)),
+	} {
+		_, err := b.Build()
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// WithConvergenceKey returns a copy of the scheme instance that uses
+// the provided secret key for deriving the randomness in
+// EncryptConvergent. The key should be at least 32 random bytes and
+// must be kept secret by the encrypting party.
+func (d *DDH) WithConvergenceKey(key []byte) *DDH {
+	c := *d
+	c.convergenceKey = &secretKey{key: append([]byte(nil), key...)}
+
+	return &c
+}
+
+// EncryptConvergent encrypts input vector x with the provided master
+// public key like Encrypt, but instead of sampling the randomness r it
+// derives it as a pseudo-random function (HMAC-SHA256 keyed by the key
+// set with WithConvergenceKey) of x and the master public key. Thus
+// encrypting equal vectors under the same master public key yields
+// equal ciphertexts, which allows deduplication of ciphertexts.
+//
+// This trades security for determinism: anyone observing ciphertexts
+// learns which of them encrypt equal vectors, and anyone able to
+// obtain encryptions of chosen vectors can test a guess of the
+// plaintext of a ciphertext. Semantic security is retained only for
+// distinct vectors, and only as long as the convergence key is secret.
+// The randomness of convergent encryptions is not checked by the guard
+// set with WithNonceGuard, as it repeats by design.
+//
+// It returns an error if no convergence key was set or encryption failed.
+func (d *DDH) EncryptConvergent(x, masterPubKey data.Vector) (data.Vector, error) {
+	if d.convergenceKey == nil {
+		return nil, fmt.Errorf("convergence key is not set")
+	}
+	key := d.convergenceKey.get()
+	if key == nil {
+		return nil, fmt.Errorf("convergence key was destroyed by Close")
+	}
+	defer zero(key)
+
+	enc, err := data.MarshalVectors([]data.Vector{x, masterPubKey})
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(enc)
+	random := internal.NewDetReader(mac.Sum(nil))
+	sampler := sample.NewUniformRangeFromReader(big.NewInt(2), d.Params.Q, random)
+
+	return d.Encrypt(x, masterPubKey, WithSampler(sampler), deterministic())
+}
+
+// deterministic marks the encryption randomness as derived
+// deterministically.
+func deterministic() EncryptOption {
+	return func(c *encryptConfig) {
+		c.deterministic = true
+	}
+}
+
+// secretKey holds a secret key shared by the copies of a scheme
+// instance, which can be destroyed when the instance is closed.
+type secretKey struct {
+	mu  sync.Mutex
+	key []byte
+}
+
+// get returns a copy of the key, or nil if it was destroyed.
+func (k *secretKey) get() []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key == nil {
+		return nil
+	}
+	return append([]byte(nil), k.key...)
+}
+
+// destroy overwrites the key with zeros and forgets it.
+func (k *secretKey) destroy() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	zero(k.key)
+	k.key = nil
+}
+
+// zero overwrites b with zeros.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
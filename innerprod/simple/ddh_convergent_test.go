@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptConvergent(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x1 := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-2)})
+	x2 := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)})
+
+	_, err = ddh.EncryptConvergent(x1, masterPubKey)
+	assert.Error(t, err, "convergence key should be required")
+
+	// the nonce guard should not reject repeated convergent encryptions
+	convergent := ddh.WithConvergenceKey([]byte("server secret")).WithNonceGuard(10)
+	c1, err := convergent.EncryptConvergent(x1, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	c1Again, err := convergent.EncryptConvergent(x1, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	c2, err := convergent.EncryptConvergent(x2, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	other, err := ddh.WithConvergenceKey([]byte("other secret")).EncryptConvergent(x1, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	assert.Equal(t, c1.String(), c1Again.String(), "equal vectors should give equal ciphertexts")
+	assert.NotEqual(t, c1.String(), c2.String(), "different vectors should give different ciphertexts")
+	assert.NotEqual(t, c1[0].String(), c2[0].String(), "different vectors should use different randomness")
+	assert.NotEqual(t, c1.String(), other.String(), "different keys should give different ciphertexts")
+
+	y := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(4)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.Decrypt(c1, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-5)), "obtained incorrect inner product")
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// CrossInnerProd computes the inner product <x1, x2> of a vector x1
+// known in the clear and a vector x2 given only as cipher, encrypted
+// under the master public key matching masterSecKey. It is a building
+// block for two-party settings where each party holds its own master
+// secret key and wants to combine its data with the encrypted data of
+// the other party.
+//
+// Trust assumptions: the caller holds masterSecKey, which suffices to
+// decrypt every coordinate of x2, thus the scheme offers no privacy of
+// x2 against the caller; the encrypting party must trust the caller
+// with the whole x2 and only the caller's restraint keeps it to the
+// inner product. Conversely, x1 is not hidden at all. The result is
+// correct only if both x1 and x2 are bounded by the bound of the
+// scheme. Computing <x1, x2> with both vectors encrypted under
+// independent master secret keys is not possible with this scheme and
+// requires quadratic functional encryption.
+func (d *DDH) CrossInnerProd(cipher, masterSecKey, x1 data.Vector) (*big.Int, error) {
+	key, err := d.DeriveKey(masterSecKey, x1)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Decrypt(cipher, key, x1)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHCrossInnerProd(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x1 := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-7), big.NewInt(1000)})
+	x2 := data.NewVector([]*big.Int{big.NewInt(-12), big.NewInt(5), big.NewInt(999)})
+	cipher, err := ddh.Encrypt(x2, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	res, err := ddh.CrossInnerProd(cipher, masterSecKey, x1)
+	if err != nil {
+		t.Fatalf("Error during cross inner product: %v", err)
+	}
+	expected, _ := x1.Dot(x2)
+	assert.Equal(t, expected.Cmp(res), 0, "obtained incorrect inner product")
+
+	_, err = ddh.CrossInnerProd(cipher, masterSecKey, data.NewVector([]*big.Int{big.NewInt(1001), big.NewInt(0), big.NewInt(0)}))
+	assert.Error(t, err, "vector exceeding the bound should be rejected")
+}
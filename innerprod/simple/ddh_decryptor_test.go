@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHDecryptor(t *testing.T) {
+	l := 3
+	bound := big.NewInt(100)
+	ddh, err := simple.NewDDHPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	dec, err := ddh.NewDecryptor()
+	if err != nil {
+		t.Fatalf("Error during decryptor creation: %v", err)
+	}
+	assert.Equal(t, uint64(1), dec.TableStats().Builds, "table should be built in advance")
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+	n := 5
+	for i := 0; i < n; i++ {
+		x, _ := data.NewRandomVector(l, sampler)
+		y, _ := data.NewRandomVector(l, sampler)
+		key, err := ddh.DeriveKey(masterSecKey, y)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		cipher, err := ddh.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		xy, err := dec.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		xyCheck, _ := x.Dot(y)
+		assert.Equal(t, 0, xy.Cmp(xyCheck), "obtained incorrect inner product")
+	}
+
+	stats := dec.TableStats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should not be rebuilt")
+	assert.Equal(t, uint64(n), stats.Reuses, "precomputed table should be reused")
+	assert.Equal(t, uint64(0), ddh.TableStats().Builds, "original instance should not cache tables")
+
+	tooLarge, err := simple.NewDDHPrecomp(l, 1024, new(big.Int).Lsh(big.NewInt(1), 30))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, err = tooLarge.NewDecryptor()
+	assert.Error(t, err, "range of inner products should be too large for a table")
+}
+
+func TestSimple_DDHDecryptorMalformedCipher(t *testing.T) {
+	l := 3
+	ddh, err := simple.NewDDHPrecomp(l, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	dec, err := ddh.NewDecryptor()
+	if err != nil {
+		t.Fatalf("Error during decryptor creation: %v", err)
+	}
+	y := data.NewConstantVector(l, big.NewInt(1))
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(data.NewConstantVector(l, big.NewInt(2)), masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// a zero in any component is rejected before the search
+	for i := range cipher {
+		malformed := append(data.Vector{}, cipher...)
+		malformed[i] = big.NewInt(0)
+		_, err := dec.Decrypt(malformed, key, y)
+		assert.True(t, errors.Is(err, internal.ErrMalformedCipher),
+			"zero component %d: expected malformed ciphertext, got %v", i, err)
+	}
+	_, err = dec.Decrypt(cipher[:l], key, y)
+	assert.True(t, errors.Is(err, internal.ErrMalformedCipher), "expected malformed ciphertext, got %v", err)
+}
+
+func benchmarkDDHDecryptMany(b *testing.B, bound *big.Int, decryptor bool) {
+	l := 10
+	ddh, err := simple.NewDDHPrecomp(l, 2048, bound)
+	if err != nil {
+		b.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		b.Fatalf("Error during master key generation: %v", err)
+	}
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+	x, _ := data.NewRandomVector(l, sampler)
+	y, _ := data.NewRandomVector(l, sampler)
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		b.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		b.Fatalf("Error during encryption: %v", err)
+	}
+
+	decrypt := ddh.Decrypt
+	b.ResetTimer()
+	if decryptor {
+		// the table is built once, within the timed code
+		dec, err := ddh.NewDecryptor()
+		if err != nil {
+			b.Fatalf("Error during decryptor creation: %v", err)
+		}
+		decrypt = dec.Decrypt
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := decrypt(cipher, key, y); err != nil {
+			b.Fatalf("Error during decryption: %v", err)
+		}
+	}
+}
+
+func BenchmarkDDH_DecryptSmallBound(b *testing.B) {
+	benchmarkDDHDecryptMany(b, big.NewInt(1000), false)
+}
+
+func BenchmarkDDHDecryptor_DecryptSmallBound(b *testing.B) {
+	benchmarkDDHDecryptMany(b, big.NewInt(1000), true)
+}
+
+func BenchmarkDDH_DecryptLargeBound(b *testing.B) {
+	benchmarkDDHDecryptMany(b, big.NewInt(1<<15), false)
+}
+
+func BenchmarkDDHDecryptor_DecryptLargeBound(b *testing.B) {
+	benchmarkDDHDecryptMany(b, big.NewInt(1<<15), true)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal"
+)
+
+// dhParameter is the DHParameter structure of PKCS #3.
+type dhParameter struct {
+	P                  *big.Int
+	G                  *big.Int
+	PrivateValueLength int `asn1:"optional"`
+}
+
+// NewDDHFromDHParams configures a new instance of the scheme in the
+// group given by DER encoded Diffie-Hellman parameters, i.e. the
+// DHParameter structure of PKCS #3 holding the modulus P and the
+// generator G, as used e.g. for the groups of RFC 7919. The optional
+// private value length is ignored. It accepts the length of input
+// vectors l, the encoded parameters, and a bound by which coordinates
+// of input vectors are bounded.
+//
+// P must be a safe prime, so that the order of the group generated by
+// G is Q = (P - 1) / 2, which is checked along with G^Q = 1 (mod P).
+//
+// It returns an error if the parameters cannot be parsed or are not
+// valid, or if precondition 2 * l * bound² is > order of the cyclic
+// group, in which case the error wraps fe.ErrBoundTooLarge.
+func NewDDHFromDHParams(l int, der []byte, bound *big.Int) (*DDH, error) {
+	var params dhParameter
+	rest, err := asn1.Unmarshal(der, &params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DH parameters: %v", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("trailing data after DH parameters")
+	}
+
+	p, g := params.P, params.G
+	one := big.NewInt(1)
+	if p.Cmp(big.NewInt(7)) < 0 || !p.ProbablyPrime(20) {
+		return nil, fmt.Errorf("modulus P of DH parameters is not a prime")
+	}
+	q := new(big.Int).Rsh(p, 1)
+	if !q.ProbablyPrime(20) {
+		return nil, fmt.Errorf("modulus P of DH parameters is not a safe prime")
+	}
+	// G in (1, P - 1) with G^Q = 1 generates the subgroup of order Q,
+	// since Q is a prime
+	if g.Cmp(one) <= 0 || g.Cmp(new(big.Int).Sub(p, one)) >= 0 ||
+		new(big.Int).Exp(g, q, p).Cmp(one) != 0 {
+		return nil, fmt.Errorf("generator G of DH parameters does not generate the subgroup of order (P - 1) / 2")
+	}
+
+	if err := internal.CheckOrderBound(l, bound, bound, q); err != nil {
+		return nil, err
+	}
+
+	return &DDH{
+		Params: &DDHParams{
+			L:     l,
+			Bound: bound,
+			G:     g,
+			P:     p,
+			Q:     q,
+		},
+	}, nil
+}
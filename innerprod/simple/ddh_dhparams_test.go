@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+// ffdhe2048DER holds the DER encoded parameters of the ffdhe2048 group
+// of RFC 7919.
+const ffdhe2048DER = "MIIBCAKCAQEA//////////+t+FRYortKmq/cViAnPTzx2LnFg84tNpWp4TZBFGQz" +
+	"+8yTnc4kmz75fS/jY2MMddj2gbICrsRhetPfHtXV/WVhJDP1H18GbtCFY2VVPe0a" +
+	"87VXE15/V8k1mE8McODmi3fipona8+/och3xWKE2rec1MKzKT0g6eXq8CrGCsyT7" +
+	"YdEIqUuyyOP7uWrat2DX9GgdT0Kj3jlN9K5W7edjcrsZCwenyO4KbXCeAvzhzffi" +
+	"7MA0BM0oNC9hkXL+nOmFg/+OTxIy7vKBg8P+OxtMb61zO7X8vC7CIAXFjvGDfRaD" +
+	"ssbzSibBsu/6iGtCOGEoXJf//////////wIBAg=="
+
+// ffdhe2048P is the modulus of the ffdhe2048 group of RFC 7919.
+const ffdhe2048P = "FFFFFFFFFFFFFFFFADF85458A2BB4A9AAFDC5620273D3CF1D8B9C583CE2D3695" +
+	"A9E13641146433FBCC939DCE249B3EF97D2FE363630C75D8F681B202AEC4617A" +
+	"D3DF1ED5D5FD65612433F51F5F066ED0856365553DED1AF3B557135E7F57C935" +
+	"984F0C70E0E68B77E2A689DAF3EFE8721DF158A136ADE73530ACCA4F483A797A" +
+	"BC0AB182B324FB61D108A94BB2C8E3FBB96ADAB760D7F4681D4F42A3DE394DF4" +
+	"AE56EDE76372BB190B07A7C8EE0A6D709E02FCE1CDF7E2ECC03404CD28342F61" +
+	"9172FE9CE98583FF8E4F1232EEF28183C3FE3B1B4C6FAD733BB5FCBC2EC22005" +
+	"C58EF1837D1683B2C6F34A26C1B2EFFA886B423861285C97FFFFFFFFFFFFFFFF"
+
+func TestSimple_NewDDHFromDHParams(t *testing.T) {
+	der, err := base64.StdEncoding.DecodeString(ffdhe2048DER)
+	if err != nil {
+		t.Fatalf("Error during decoding the parameters: %v", err)
+	}
+	ddh, err := simple.NewDDHFromDHParams(2, der, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	p, _ := new(big.Int).SetString(ffdhe2048P, 16)
+	assert.Equal(t, 0, ddh.Params.P.Cmp(p))
+	assert.Equal(t, int64(2), ddh.Params.G.Int64())
+	assert.Equal(t, 0, ddh.Params.Q.Cmp(new(big.Int).Rsh(p, 1)))
+
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-4)})
+	y := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(6)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-39), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = simple.NewDDHFromDHParams(2, der, new(big.Int).Lsh(big.NewInt(1), 1100))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	_, err = simple.NewDDHFromDHParams(2, der[:len(der)-1], big.NewInt(100))
+	assert.Error(t, err, "truncated parameters should be rejected")
+	_, err = simple.NewDDHFromDHParams(2, append(der, 0), big.NewInt(100))
+	assert.Error(t, err, "trailing data should be rejected")
+
+	invalid := func(p, g *big.Int) []byte {
+		b, err := asn1.Marshal(struct{ P, G *big.Int }{p, g})
+		if err != nil {
+			t.Fatalf("Error during encoding the parameters: %v", err)
+		}
+		return b
+	}
+	_, err = simple.NewDDHFromDHParams(2, invalid(p, big.NewInt(1)), big.NewInt(100))
+	assert.Error(t, err, "generator 1 should be rejected")
+	_, err = simple.NewDDHFromDHParams(2, invalid(p, new(big.Int).Sub(p, big.NewInt(1))), big.NewInt(100))
+	assert.Error(t, err, "generator of order 2 should be rejected")
+	_, err = simple.NewDDHFromDHParams(2, invalid(new(big.Int).Add(p, big.NewInt(2)), big.NewInt(2)), big.NewInt(100))
+	assert.Error(t, err, "modulus that is not a safe prime should be rejected")
+}
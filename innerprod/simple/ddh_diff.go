@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// EncryptDiff encrypts the difference a - b of vectors a and b with
+// the provided master public key, without building the difference
+// vector. The ciphertext is the same as the one produced by Encrypt
+// for a - b with the same randomness and options, and only the
+// differences need to be bounded, thus a and b may themselves exceed
+// the bound. Each difference is computed in a single temporary integer.
+// It returns an error if the master public key, a or b are not of
+// length l, if a difference is not bounded, in which case the error is
+// a *data.BoundViolationError, or if encryption failed.
+func (d *DDH) EncryptDiff(a, b data.Vector, masterPubKey data.Vector, opts ...EncryptOption) (data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckEncryptLengths(a, masterPubKey, d.Params.L); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckInputLength(b, d.Params.L); err != nil {
+		return nil, err
+	}
+	diff := new(big.Int)
+	for i := range a {
+		if diff.Sub(a[i], b[i]).CmpAbs(d.Params.Bound) > 0 {
+			return nil, &data.BoundViolationError{
+				Index: i,
+				Value: new(big.Int).Set(diff),
+				Bound: new(big.Int).Set(d.Params.Bound),
+			}
+		}
+	}
+
+	return d.encryptWith(d.newEncryptConfig(opts), masterPubKey, func(i int) *big.Int {
+		// g^(a_i - b_i)
+		return internal.ModExp(d.Params.G, diff.Sub(a[i], b[i]), d.Params.P)
+	})
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptDiff(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// a and b exceed the bound, their difference does not
+	a := data.NewVector([]*big.Int{big.NewInt(1000), big.NewInt(-5), big.NewInt(150)})
+	b := data.NewVector([]*big.Int{big.NewInt(950), big.NewInt(15), big.NewInt(250)})
+	cipher, err := ddh.EncryptDiff(a, b, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	expected, _ := a.Sub(b).Dot(y)
+	assert.Equal(t, 0, xy.Cmp(expected), "obtained incorrect inner product")
+
+	b[2] = big.NewInt(251)
+	_, err = ddh.EncryptDiff(a, b, masterPubKey)
+	var boundErr *data.BoundViolationError
+	if assert.True(t, errors.As(err, &boundErr), "unbounded difference should be rejected") {
+		assert.Equal(t, 2, boundErr.Index)
+		assert.Equal(t, int64(-101), boundErr.Value.Int64())
+	}
+	_, err = ddh.EncryptDiff(a, b[:2], masterPubKey)
+	assert.True(t, errors.Is(err, internal.ErrMalformedInput), "vectors of different length should be rejected")
+	_, err = ddh.EncryptDiff(a[:2], b[:2], masterPubKey[:2])
+	assert.True(t, errors.Is(err, internal.ErrMalformedPubKey), "master public key of wrong length should be rejected")
+}
+
+func TestSimple_DDHEncryptDiffOptions(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	a := data.NewVector([]*big.Int{big.NewInt(1000), big.NewInt(-5), big.NewInt(150)})
+	b := data.NewVector([]*big.Int{big.NewInt(950), big.NewInt(15), big.NewInt(250)})
+
+	// the options of Encrypt apply
+	sampler := &fixedSampler{value: big.NewInt(12345)}
+	var progress []float64
+	cipher, err := ddh.EncryptDiff(a, b, masterPubKey, simple.WithSampler(sampler),
+		simple.WithProgress(1, func(f float64) { progress = append(progress, f) }))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	expected, err := ddh.Encrypt(a.Sub(b), masterPubKey, simple.WithSampler(sampler))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, expected, cipher)
+	assert.Equal(t, 3, len(progress), "progress should be reported")
+
+	// so does the source of randomness of the scheme
+	var ciphers []data.Vector
+	for i := 0; i < 2; i++ {
+		cipher, err := ddh.WithRand(internal.NewDetReader([]byte("seed"))).EncryptDiff(a, b, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		ciphers = append(ciphers, cipher)
+	}
+	assert.Equal(t, ciphers[0], ciphers[1], "ciphertexts should depend only on the seed")
+}
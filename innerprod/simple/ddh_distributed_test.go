@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHFinishDecryptWithExponent(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	h, err := ddh.DecryptToGroupElement(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	// split [-bound, bound] into 4 ranges searched separately
+	bound := ddh.SearchBound()
+	assert.Equal(t, int64(3*100*100), bound.Int64())
+	width := new(big.Int).Div(bound, big.NewInt(2))
+	var e *big.Int
+	for lo := new(big.Int).Neg(bound); lo.Cmp(bound) <= 0; lo = new(big.Int).Add(lo, width) {
+		hi := new(big.Int).Add(lo, width)
+		hi.Sub(hi, big.NewInt(1))
+		if hi.Cmp(bound) > 0 {
+			hi.Set(bound)
+		}
+		res, ok, err := dlog.SearchRange(h, ddh.Params.G, ddh.Params.P, lo, hi)
+		if err != nil {
+			t.Fatalf("Error during search: %v", err)
+		}
+		if ok {
+			e = res
+		}
+	}
+	if e == nil {
+		t.Fatalf("Discrete logarithm was not found")
+	}
+
+	xy, err := ddh.FinishDecryptWithExponent(h, e)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = ddh.FinishDecryptWithExponent(h, big.NewInt(-332))
+	assert.Error(t, err, "wrong exponent should be rejected")
+	tooLarge := new(big.Int).Add(bound, big.NewInt(1))
+	_, err = ddh.FinishDecryptWithExponent(h, tooLarge)
+	assert.Error(t, err, "exponent exceeding the bound should be rejected")
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// DecryptWithDP decrypts the inner product of x and y like Decrypt,
+// and adds discrete Laplace noise to the result, making the revealed
+// value epsilon-differentially private with respect to a change of a
+// single coordinate of x.
+//
+// Since every coordinate of x is bounded by the bound B of the scheme,
+// changing a single coordinate changes the inner product by at most
+// 2 * B * max|y_i|, which is the sensitivity the noise is scaled to.
+// Smaller epsilon gives stronger privacy and larger noise. Note that
+// the privacy holds only as long as the exact inner product is never
+// revealed, e.g. by decrypting with Decrypt as well.
+//
+// It returns an error if epsilon is not positive, the scale of the
+// noise 2 * B * max|y_i| / epsilon exceeds
+// sample.MaxDiscreteLaplaceScale, or decryption fails.
+func (d *DDH) DecryptWithDP(cipher data.Vector, key *big.Int, y data.Vector, epsilon float64) (*big.Int, error) {
+	if !(epsilon > 0) {
+		return nil, fmt.Errorf("epsilon should be positive")
+	}
+
+	res, err := d.Decrypt(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	yMax := new(big.Int)
+	for _, yi := range y {
+		if abs := new(big.Int).Abs(yi); abs.Cmp(yMax) > 0 {
+			yMax = abs
+		}
+	}
+	sensitivity := new(big.Int).Mul(d.Params.Bound, yMax)
+	sensitivity.Lsh(sensitivity, 1)
+	if sensitivity.Sign() == 0 {
+		return res, nil
+	}
+
+	sensitivityF, _ := new(big.Float).SetInt(sensitivity).Float64()
+	scale := sensitivityF / epsilon
+	if !(scale <= sample.MaxDiscreteLaplaceScale) {
+		return nil, fmt.Errorf("scale of the noise %v is too large to be sampled exactly", scale)
+	}
+	noise, err := sample.NewDiscreteLaplace(scale).Sample()
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Add(res, noise), nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHDecryptWithDP(t *testing.T) {
+	ddh, err := simple.NewDDH(2, 128, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(-2)})
+	y := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(1)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	_, err = ddh.DecryptWithDP(cipher, key, y, 0)
+	assert.Error(t, err, "epsilon should be positive")
+	// sensitivity / epsilon overflows to +Inf
+	_, err = ddh.DecryptWithDP(cipher, key, y, 1e-320)
+	assert.Error(t, err, "infinite scale should be rejected")
+	// finite scale, too large to be sampled exactly
+	_, err = ddh.DecryptWithDP(cipher, key, y, 1e-15)
+	assert.Error(t, err, "too large scale should be rejected")
+
+	// sensitivity is 2 * 10 * 3 = 60, with epsilon 2 the noise
+	// has scale 30 and variance 2q / (1 - q)^2 ≈ 1800,
+	// where q = exp(-1/30)
+	n := 1000
+	sum, sumSq := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		res, err := ddh.DecryptWithDP(cipher, key, y, 2)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		noise := float64(res.Int64() - 10)
+		sum += noise
+		sumSq += noise * noise
+	}
+	mean := sum / float64(n)
+	variance := sumSq/float64(n) - mean*mean
+	q := math.Exp(-1.0 / 30)
+	expected := 2 * q / ((1 - q) * (1 - q))
+
+	assert.True(t, math.Abs(mean) < 8, "mean of the noise is too far from 0: %v", mean)
+	assert.True(t, variance > 0.7*expected, "variance of the noise is too small: %v", variance)
+	assert.True(t, variance < 1.3*expected, "variance of the noise is too big: %v", variance)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// Ciphertext is an envelope holding a ciphertext of the DDH scheme
+// together with the identifier of the scheme that produced it, and the
+// length of vectors and the bound the scheme was configured with.
+type Ciphertext struct {
+	SchemeID string
+	L        int
+	Bound    *big.Int
+	C        data.Vector
+}
+
+// DerivedKey is an envelope holding a functional encryption key of
+// the DDH scheme together with the identifier of the scheme that
+// derived it.
+type DerivedKey struct {
+	SchemeID string
+	Key      *big.Int
+}
+
+// SchemeID returns a short identifier of the scheme, derived from the
+// length of input vectors and the group (G, P and Q). Keys and
+// ciphertexts are interchangeable between scheme instances with equal
+// identifiers. The bound is not part of the identifier, since instances
+// that differ only in the bound share keys and ciphertexts.
+func (d *DDH) SchemeID() string {
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(d.Params.L))
+	h.Write(buf[:])
+	for _, v := range []*big.Int{d.Params.G, d.Params.P, d.Params.Q} {
+		b := v.Bytes()
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// EncryptEnvelope encrypts input vector x like Encrypt, and returns
+// the ciphertext stamped with the identifier of the scheme.
+func (d *DDH) EncryptEnvelope(x, masterPubKey data.Vector, opts ...EncryptOption) (*Ciphertext, error) {
+	c, err := d.Encrypt(x, masterPubKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{
+		SchemeID: d.SchemeID(),
+		L:        d.Params.L,
+		Bound:    new(big.Int).Set(d.Params.Bound),
+		C:        c,
+	}, nil
+}
+
+// DeriveKeyEnvelope derives the functional encryption key for y like
+// DeriveKey, and returns it stamped with the identifier of the scheme.
+func (d *DDH) DeriveKeyEnvelope(masterSecKey, y data.Vector) (*DerivedKey, error) {
+	key, err := d.DeriveKey(masterSecKey, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DerivedKey{
+		SchemeID: d.SchemeID(),
+		Key:      key,
+	}, nil
+}
+
+// CheckCompatible checks whether the key and the ciphertext were
+// produced by the same scheme. It returns an error if they were not,
+// in which case decryption would yield a wrong inner product or fail.
+func CheckCompatible(key *DerivedKey, cipher *Ciphertext) error {
+	if key.SchemeID != cipher.SchemeID {
+		return fmt.Errorf("key of scheme %s cannot decrypt ciphertext of scheme %s",
+			key.SchemeID, cipher.SchemeID)
+	}
+
+	return nil
+}
+
+// DecryptEnvelope decrypts the inner product of x and y like Decrypt,
+// but first checks that the key and the ciphertext were produced by
+// this scheme, i.e. by a scheme with the same group and length of
+// vectors. Unless the scheme has a separate bound on y, y is checked
+// against the bound stamped in the envelope, thus a decrypting party
+// configured with a larger bound accepts only vectors y within the
+// bound of the encrypting party.
+//
+// The envelope is not trusted to configure the decryption: its bound
+// must not exceed the bound of the scheme, and the inner product is
+// searched for within the bound of the scheme, as in Decrypt.
+//
+// It returns an error if the key or the ciphertext does not match the
+// scheme, or decryption failed.
+func (d *DDH) DecryptEnvelope(cipher *Ciphertext, key *DerivedKey, y data.Vector) (*big.Int, error) {
+	if err := CheckCompatible(key, cipher); err != nil {
+		return nil, err
+	}
+	if id := d.SchemeID(); cipher.SchemeID != id {
+		return nil, fmt.Errorf("ciphertext of scheme %s cannot be decrypted by scheme %s",
+			cipher.SchemeID, id)
+	}
+	if cipher.L != d.Params.L || len(cipher.C) != d.Params.L+1 {
+		return nil, internal.ErrMalformedCipher
+	}
+	if cipher.Bound == nil || cipher.Bound.Sign() <= 0 {
+		return nil, fmt.Errorf("bound of the ciphertext should be positive")
+	}
+	if cipher.Bound.Cmp(d.Params.Bound) > 0 {
+		return nil, fmt.Errorf("bound of the ciphertext %s exceeds the bound of the scheme %s",
+			cipher.Bound, d.Params.Bound)
+	}
+	// y is bounded by the separate bound of the scheme if set,
+	// otherwise by the bound of the envelope
+	boundY := d.Params.BoundY
+	if boundY == nil {
+		boundY = cipher.Bound
+	}
+	if err := internal.CheckOrderBound(cipher.L, cipher.Bound, boundY, d.Params.Q); err != nil {
+		return nil, err
+	}
+	if err := y.CheckBound(boundY); err != nil {
+		return nil, err
+	}
+
+	return d.Decrypt(cipher.C, key.Key, y)
+}
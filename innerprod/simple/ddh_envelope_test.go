@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEnvelope(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	other, err := simple.NewDDHPrecomp(2, 1536, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.NotEqual(t, ddh.SchemeID(), other.SchemeID())
+	tight, _ := ddh.WithTighterBound(big.NewInt(10))
+	assert.Equal(t, ddh.SchemeID(), tight.SchemeID(), "the bound should not affect the identifier")
+
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	otherSecKey, otherPubKey, err := other.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(5), big.NewInt(-6)})
+	y := data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(3)})
+	cipher, err := ddh.EncryptEnvelope(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKeyEnvelope(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.NoError(t, simple.CheckCompatible(key, cipher))
+
+	xy, err := ddh.DecryptEnvelope(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-8)), "obtained incorrect inner product")
+
+	otherCipher, err := other.EncryptEnvelope(x, otherPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	otherKey, err := other.DeriveKeyEnvelope(otherSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Error(t, simple.CheckCompatible(key, otherCipher))
+	_, err = ddh.DecryptEnvelope(otherCipher, key, y)
+	assert.Error(t, err, "crossed key and ciphertext should be rejected")
+	_, err = ddh.DecryptEnvelope(otherCipher, otherKey, y)
+	assert.Error(t, err, "ciphertext of another scheme should be rejected")
+}
+
+func TestSimple_DDHDecryptEnvelopeBound(t *testing.T) {
+	decryptor, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	// the encryptor is configured with a smaller bound
+	encryptor, err := decryptor.WithTighterBound(big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during bound tightening: %v", err)
+	}
+	masterSecKey, masterPubKey, err := encryptor.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(9), big.NewInt(-6)})
+	y := data.NewVector([]*big.Int{big.NewInt(5), big.NewInt(3)})
+	cipher, err := encryptor.EncryptEnvelope(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, 2, cipher.L)
+	assert.Equal(t, 0, cipher.Bound.Cmp(big.NewInt(10)))
+	key, err := encryptor.DeriveKeyEnvelope(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	xy, err := decryptor.DecryptEnvelope(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(27)), "obtained incorrect inner product")
+
+	tooLarge := data.NewVector([]*big.Int{big.NewInt(11), big.NewInt(3)})
+	_, err = decryptor.DecryptEnvelope(cipher, key, tooLarge)
+	assert.Error(t, err, "y should be checked against the envelope's bound")
+
+	forged := *cipher
+	forged.L = 3
+	_, err = decryptor.DecryptEnvelope(&forged, key, y)
+	assert.Error(t, err, "envelope of a different length should be rejected")
+	forged = *cipher
+	forged.Bound = big.NewInt(1001)
+	_, err = decryptor.DecryptEnvelope(&forged, key, y)
+	assert.Error(t, err, "envelope bound exceeding the scheme's bound should be rejected")
+	forged.Bound = new(big.Int).Lsh(big.NewInt(1), 600)
+	_, err = decryptor.DecryptEnvelope(&forged, key, y)
+	assert.Error(t, err, "envelope bound violating the precondition should be rejected")
+}
+
+func TestSimple_DDHDecryptEnvelopeBoundY(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	weighted, err := ddh.WithBoundY(big.NewInt(1000000))
+	if err != nil {
+		t.Fatalf("Error during setting the bound of y: %v", err)
+	}
+	masterSecKey, masterPubKey, err := weighted.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(-10), big.NewInt(7)})
+	y := data.NewVector([]*big.Int{big.NewInt(1000000), big.NewInt(-999999)})
+	cipher, err := weighted.EncryptEnvelope(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := weighted.DeriveKeyEnvelope(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// y is checked against the separate bound of the scheme
+	xy, err := weighted.DecryptEnvelope(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-16999993)), "obtained incorrect inner product")
+
+	// parameters violating 2 * l * bound * boundY <= Q are rejected
+	params := *weighted.Params
+	params.BoundY = new(big.Int).Lsh(big.NewInt(1), 1020)
+	_, err = simple.NewDDHFromParams(&params).DecryptEnvelope(cipher, key, y)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+}
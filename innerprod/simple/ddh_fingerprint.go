@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+)
+
+// fingerprintDomain prefixes the encoding hashed by Fingerprint. It
+// must not change, so that fingerprints are stable across versions.
+const fingerprintDomain = "gofe/simple/DDHParams/v1"
+
+// Fingerprint returns the SHA-256 hash of the canonical encoding of the
+// parameters of the scheme in hexadecimal, e.g. to check that a scheme
+// reconstructed with NewDDHFromParams has exactly the intended
+// parameters. It depends only on the values of the parameters, and
+// BoundY set to nil gives the same fingerprint as BoundY equal to
+// Bound, since both bound y by Bound.
+//
+// The canonical encoding is the string "gofe/simple/DDHParams/v1"
+// followed by L, Bound, the bound of y, G, P, Q and Scale, where the
+// integers L and Scale are encoded as 8 bytes in big-endian two's
+// complement, and the big integers by a sign byte (1 if negative, 0
+// otherwise), the 8 byte big-endian length of their absolute value,
+// and its minimal big-endian bytes.
+func (d *DDH) Fingerprint() string {
+	h := sha256.New()
+	h.Write([]byte(fingerprintDomain))
+	var buf [8]byte
+	writeInt := func(x int) {
+		binary.BigEndian.PutUint64(buf[:], uint64(int64(x)))
+		h.Write(buf[:])
+	}
+	writeBig := func(x *big.Int) {
+		if x.Sign() < 0 {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+		b := x.Bytes()
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+
+	writeInt(d.Params.L)
+	for _, x := range []*big.Int{d.Params.Bound, d.boundY(), d.Params.G, d.Params.P, d.Params.Q} {
+		writeBig(x)
+	}
+	writeInt(d.Params.Scale)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
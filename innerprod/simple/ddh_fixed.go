@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// NewDDHForFixedPoint configures a new instance of the scheme for
+// encrypting signed fixed-point vectors. It accepts the length of
+// input vectors l, the bit length of the modulus (we are operating
+// in the Z_p group), the maximal absolute value maxAbs of the
+// (unscaled) coordinates, and the number of decimal digits scale
+// kept by the fixed-point representation.
+//
+// Coordinates are expected to be encrypted as integers
+// round(x_i * 10^scale), thus the bound of the scheme is set to
+// ceil(maxAbs * 10^scale). The scale is stored in the parameters
+// of the scheme and used by DecryptFixed.
+//
+// It returns an error in case the scheme could not be properly
+// configured, or if precondition 2 * l * bound² is > order of the cyclic
+// group, in which case the error wraps fe.ErrBoundTooLarge.
+func NewDDHForFixedPoint(l, modulusLength int, maxAbs float64, scale int) (*DDH, error) {
+	bound, err := fixedPointBound(maxAbs, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	ddh, err := NewDDH(l, modulusLength, bound)
+	if err != nil {
+		return nil, err
+	}
+	ddh.Params.Scale = scale
+
+	return ddh, nil
+}
+
+// fixedPointBound returns ceil(maxAbs * 10^scale). maxAbs is taken
+// by its shortest decimal representation, so that e.g. 1.1 with
+// scale 1 gives 11 and not 12.
+func fixedPointBound(maxAbs float64, scale int) (*big.Int, error) {
+	if math.IsNaN(maxAbs) || math.IsInf(maxAbs, 0) || maxAbs <= 0 {
+		return nil, fmt.Errorf("maximal absolute value should be a positive finite number")
+	}
+	if scale < 0 {
+		return nil, fmt.Errorf("scale should not be negative")
+	}
+
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(maxAbs, 'g', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("failed to convert %v to a rational number", maxAbs)
+	}
+	r.Mul(r, new(big.Rat).SetInt(scaleFactor(scale)))
+
+	bound, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		bound.Add(bound, big.NewInt(1))
+	}
+
+	return bound, nil
+}
+
+// scaleFactor returns 10^scale.
+func scaleFactor(scale int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+}
+
+// DecryptFixed accepts the encrypted vector of a fixed-point vector x
+// scaled by 10^Params.Scale, functional encryption key, and an integer
+// vector y. It returns the inner product of x and y, i.e. the result
+// of Decrypt divided by 10^Params.Scale.
+// If decryption failed, error is returned.
+func (d *DDH) DecryptFixed(cipher data.Vector, key *big.Int, y data.Vector) (*big.Rat, error) {
+	res, err := d.Decrypt(cipher, key, y)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).SetFrac(res, scaleFactor(d.Params.Scale)), nil
+}
+
+// DeriveKeyFloat works like DeriveKey, but it accepts the vector y as
+// fixed-point numbers with scale decimal digits. The key is derived for
+// the integer vector round(y_i * 10^scale), see DecryptFloat. Each y_i
+// is taken by its shortest decimal representation and must be an
+// integer after scaling, e.g. 0.25 is accepted with scale 2, but not
+// with scale 1. It returns an error if y_i is not finite or not exact
+// after scaling, or if the key could not be derived.
+func (d *DDH) DeriveKeyFloat(masterSecKey data.Vector, y []float64, scale int) (*big.Int, error) {
+	yInt, err := scaleFloats(y, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DeriveKey(masterSecKey, yInt)
+}
+
+// DecryptFloat accepts the encrypted vector x, the functional
+// encryption key obtained by DeriveKeyFloat, and the vector y as
+// fixed-point numbers with scale decimal digits. It returns the inner
+// product of x and y, i.e. the result of Decrypt for the scaled y
+// divided by 10^scale. If x is itself a fixed-point vector (see
+// NewDDHForFixedPoint), the result is additionally divided by
+// 10^Params.Scale. It returns an error if y_i is not finite or not
+// exact after scaling, or if decryption failed.
+func (d *DDH) DecryptFloat(cipher data.Vector, key *big.Int, y []float64, scale int) (*big.Rat, error) {
+	yInt, err := scaleFloats(y, scale)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.Decrypt(cipher, key, yInt)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).SetFrac(res, scaleFactor(scale+d.Params.Scale)), nil
+}
+
+// scaleFloats returns the integer vector y * 10^scale, taking each
+// y_i by its shortest decimal representation. It returns an error if
+// any of y_i is not finite or y_i * 10^scale is not an integer.
+func scaleFloats(y []float64, scale int) (data.Vector, error) {
+	if scale < 0 {
+		return nil, fmt.Errorf("scale should not be negative")
+	}
+
+	f := new(big.Rat).SetInt(scaleFactor(scale))
+	v := make(data.Vector, len(y))
+	for i, yi := range y {
+		if math.IsNaN(yi) || math.IsInf(yi, 0) {
+			return nil, fmt.Errorf("coordinate %d should be a finite number", i)
+		}
+		r, ok := new(big.Rat).SetString(strconv.FormatFloat(yi, 'g', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("failed to convert %v to a rational number", yi)
+		}
+		r.Mul(r, f)
+		if !r.IsInt() {
+			return nil, fmt.Errorf("coordinate %d is not an integer when scaled by 10^%d", i, scale)
+		}
+		v[i] = new(big.Int).Set(r.Num())
+	}
+
+	return v, nil
+}
+
+// EncryptFloats works like Encrypt, but it accepts the vector x as
+// floating-point numbers. Each x_i is taken by its shortest decimal
+// representation, scaled by 10^scale and rounded to the nearest
+// integer, with halves rounded away from zero. The resulting integer
+// vector is encrypted, so it is to be decrypted e.g. with
+// DecryptFixed on an instance with Params.Scale = scale (see
+// NewDDHForFixedPoint).
+// It returns an error if x_i is not finite, listing the coordinates
+// that exceed the bound of the scheme after scaling, or if encryption
+// failed.
+func (d *DDH) EncryptFloats(x []float64, scale int, masterPubKey data.Vector) (data.Vector, error) {
+	if scale < 0 {
+		return nil, fmt.Errorf("scale should not be negative")
+	}
+
+	f := new(big.Rat).SetInt(scaleFactor(scale))
+	xInt := make(data.Vector, len(x))
+	var overflow []int
+	for i, xi := range x {
+		if math.IsNaN(xi) || math.IsInf(xi, 0) {
+			return nil, fmt.Errorf("coordinate %d should be a finite number", i)
+		}
+		r, ok := new(big.Rat).SetString(strconv.FormatFloat(xi, 'g', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("failed to convert %v to a rational number", xi)
+		}
+		xInt[i] = roundRat(r.Mul(r, f))
+		if new(big.Int).Abs(xInt[i]).Cmp(d.Params.Bound) > 0 {
+			overflow = append(overflow, i)
+		}
+	}
+	if overflow != nil {
+		return nil, fmt.Errorf("coordinates %v exceed the bound when scaled by 10^%d", overflow, scale)
+	}
+
+	return d.Encrypt(xInt, masterPubKey)
+}
+
+// roundRat returns r rounded to the nearest integer, with halves
+// rounded away from zero.
+func roundRat(r *big.Rat) *big.Int {
+	// (2|num| + denom) / (2 denom), truncated
+	num := new(big.Int).Abs(r.Num())
+	num.Lsh(num, 1)
+	num.Add(num, r.Denom())
+	res := num.Quo(num, new(big.Int).Lsh(r.Denom(), 1))
+	if r.Sign() < 0 {
+		res.Neg(res)
+	}
+
+	return res
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHForFixedPoint(t *testing.T) {
+	ddh, err := simple.NewDDHForFixedPoint(3, 512, 12.5, 2)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, big.NewInt(1250), ddh.Params.Bound)
+	assert.Equal(t, 2, ddh.Params.Scale)
+
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	// x = (12.5, -0.01, 3.14) scaled by 10^2
+	x := data.NewVector([]*big.Int{big.NewInt(1250), big.NewInt(-1), big.NewInt(314)})
+	y := data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(100), big.NewInt(-1)})
+
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	decryptor := simple.NewDDHFromParams(ddh.Params)
+	xy, err := decryptor.DecryptFixed(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	// 25 - 1 - 3.14
+	assert.Equal(t, 0, xy.Cmp(big.NewRat(2086, 100)), "obtained incorrect inner product")
+}
+
+func TestSimple_DDHForFixedPointBound(t *testing.T) {
+	ddh, err := simple.NewDDHForFixedPoint(2, 128, 1.1, 1)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, big.NewInt(11), ddh.Params.Bound)
+
+	ddh, err = simple.NewDDHForFixedPoint(2, 128, 0.123, 2)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, big.NewInt(13), ddh.Params.Bound)
+
+	_, err = simple.NewDDHForFixedPoint(2, 128, -1, 2)
+	assert.Error(t, err)
+	_, err = simple.NewDDHForFixedPoint(2, 128, 1, -2)
+	assert.Error(t, err)
+	_, err = simple.NewDDHForFixedPoint(2, 128, 1e30, 10)
+	assert.Error(t, err, "bound exceeding the group order should be rejected")
+}
+
+func TestSimple_DDHFloatY(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-2), big.NewInt(7)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	y := []float64{0.25, -1.5, 0.1}
+	key, err := ddh.DeriveKeyFloat(masterSecKey, y, 2)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.DecryptFloat(cipher, key, y, 2)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	// 3 * 0.25 - 2 * -1.5 + 7 * 0.1 = 4.45
+	assert.Equal(t, 0, xy.Cmp(big.NewRat(445, 100)), "obtained incorrect inner product")
+
+	// the key equals the key of the scaled integer vector
+	keyInt, err := ddh.DeriveKey(masterSecKey, data.NewVector([]*big.Int{big.NewInt(25), big.NewInt(-150), big.NewInt(10)}))
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, keyInt, key)
+
+	_, err = ddh.DeriveKeyFloat(masterSecKey, y, 1)
+	assert.Error(t, err, "0.25 is not exact with scale 1")
+	_, err = ddh.DecryptFloat(cipher, key, []float64{0.25, math.NaN(), 0}, 2)
+	assert.Error(t, err, "NaN should be rejected")
+	_, err = ddh.DeriveKeyFloat(masterSecKey, []float64{math.Inf(1), 0, 0}, 2)
+	assert.Error(t, err, "infinity should be rejected")
+	_, err = ddh.DeriveKeyFloat(masterSecKey, y, -1)
+	assert.Error(t, err, "negative scale should be rejected")
+	_, err = ddh.DeriveKeyFloat(masterSecKey, []float64{10.01, 0, 0}, 2)
+	assert.Error(t, err, "scaled y out of bound should be rejected")
+
+	// fixed-point x and y
+	fixed, err := simple.NewDDHForFixedPoint(2, 512, 10, 1)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err = fixed.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	// x = (1.5, -0.3)
+	cipher, err = fixed.Encrypt(data.NewVector([]*big.Int{big.NewInt(15), big.NewInt(-3)}), masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	y = []float64{2, 0.5}
+	key, err = fixed.DeriveKeyFloat(masterSecKey, y, 1)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err = fixed.DecryptFloat(cipher, key, y, 1)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewRat(285, 100)), "obtained incorrect inner product")
+}
+
+func TestSimple_DDHEncryptFloats(t *testing.T) {
+	ddh, err := simple.NewDDHForFixedPoint(3, 512, 12.5, 2)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(100), big.NewInt(-1)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// rounded to (1250, -1, 315)
+	cipher, err := ddh.EncryptFloats([]float64{12.5, -0.005, 3.145}, 2, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.DecryptFixed(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	// 25 - 1 - 3.15
+	assert.Equal(t, 0, xy.Cmp(big.NewRat(2085, 100)), "obtained incorrect inner product")
+
+	_, err = ddh.EncryptFloats([]float64{12.506, 0, -13}, 2, masterPubKey)
+	if assert.Error(t, err, "coordinates exceeding the bound should be rejected") {
+		assert.Contains(t, err.Error(), "[0 2]")
+	}
+	_, err = ddh.EncryptFloats([]float64{math.NaN(), 0, 0}, 2, masterPubKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// SubtractPublic takes a ciphertext of x, a public vector p and the
+// master public key, and returns a ciphertext of x - p, which decrypts
+// to <x - p, y> with a key for y.
+//
+// Since ct_i = mpk_i^r * g^x_i, multiplying ct_i by g^(-p_i) already
+// gives a ciphertext of x - p with the same randomness r, i.e. the
+// encryption of -p with zero randomness is added, which does not need
+// the master public key. The result is additionally re-randomized by
+// adding an encryption of the zero vector under the master public key
+// (ct_0 * g^r', ct_i * mpk_i^r'), so it cannot be linked to the
+// original ciphertext. Decryption succeeds only if the coordinates of
+// x - p are within the bound of the scheme. The randomness r' is
+// sampled as for Encrypt, from the source set with WithRand, and
+// checked by the nonce guard of the scheme.
+//
+// It returns an error if the lengths of the vectors do not match the
+// scheme or sampling of the randomness failed.
+func (d *DDH) SubtractPublic(cipher, p, masterPubKey data.Vector) (data.Vector, error) {
+	l := d.Params.L
+	if err := internal.CheckCiphertextLength(cipher, l+1); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckEncryptLengths(p, masterPubKey, l); err != nil {
+		return nil, err
+	}
+
+	config := d.newEncryptConfig(nil)
+	r, err := d.sampleNonce(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// ct * (g^r', mpk_i^r' * g^(-p_i))
+	res := d.encryptWithNonce(config, r, masterPubKey, nil, func(i int) *big.Int {
+		ct := internal.ModExp(d.Params.G, new(big.Int).Neg(p[i]), d.Params.P)
+		return ct.Mod(ct.Mul(ct, cipher[i+1]), d.Params.P)
+	})
+	res[0].Mod(res[0].Mul(res[0], cipher[0]), d.Params.P)
+
+	return res, nil
+}
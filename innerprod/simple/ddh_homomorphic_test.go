@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHSubtractPublic(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(50), big.NewInt(-20), big.NewInt(7)})
+	p := data.NewVector([]*big.Int{big.NewInt(60), big.NewInt(-30), big.NewInt(7)})
+	y := data.NewVector([]*big.Int{big.NewInt(3), big.NewInt(-2), big.NewInt(100)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	diff, err := ddh.SubtractPublic(cipher, p, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during subtraction: %v", err)
+	}
+	assert.NotEqual(t, cipher[0].String(), diff[0].String(), "ciphertext should be re-randomized")
+
+	// <x - p, y> = <(-10, 10, 0), (3, -2, 100)> = -50
+	xy, err := ddh.Decrypt(diff, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-50)), "obtained incorrect inner product")
+
+	_, err = ddh.SubtractPublic(cipher, p[1:], masterPubKey)
+	assert.Error(t, err, "vector of a wrong length should be rejected")
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// InvariantViolation describes a case in which decryption did not
+// yield the inner product of the encrypted vector x and vector y.
+type InvariantViolation struct {
+	// iteration in which the violation occurred
+	Iteration int
+	X         data.Vector
+	Y         data.Vector
+	// the inner product of x and y
+	Expected *big.Int
+	// the decrypted value, nil if decryption failed
+	Got *big.Int
+	// the error of decryption, nil if decryption succeeded
+	Err error
+}
+
+func (e *InvariantViolation) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("iteration %d: decryption of <x, y> = %s failed for x = [%s ], y = [%s ]: %v",
+			e.Iteration, e.Expected, e.X, e.Y, e.Err)
+	}
+	return fmt.Sprintf("iteration %d: decryption yielded %s instead of <x, y> = %s for x = [%s ], y = [%s ]",
+		e.Iteration, e.Got, e.Expected, e.X, e.Y)
+}
+
+// RunInvariantChecks checks that the scheme works correctly for its
+// parameters, see RunInvariantChecksFromReader. The randomness is read
+// from crypto/rand.
+func RunInvariantChecks(scheme *DDH, iterations int) error {
+	return RunInvariantChecksFromReader(scheme, iterations, rand.Reader)
+}
+
+// RunInvariantChecksFromReader checks that the scheme works correctly
+// for its parameters. In each of the iterations it generates master
+// keys, random vectors x and y bounded by the bounds of the scheme, a
+// ciphertext of x and a key for y, and checks that decryption yields
+// the inner product of x and y. All the randomness, including the
+// master keys and the encryption randomness, is read from random,
+// thus the checks are reproducible with a deterministic reader.
+//
+// It returns an *InvariantViolation describing the first failing case,
+// or another error if the checks could not be run.
+func RunInvariantChecksFromReader(scheme *DDH, iterations int, random io.Reader) error {
+	one := big.NewInt(1)
+	boundX := scheme.Params.Bound
+	boundY := scheme.boundY()
+	samplerX := sample.NewUniformRangeFromReader(new(big.Int).Neg(boundX), new(big.Int).Add(boundX, one), random)
+	samplerY := sample.NewUniformRangeFromReader(new(big.Int).Neg(boundY), new(big.Int).Add(boundY, one), random)
+	samplerQ := sample.NewUniformRangeFromReader(big.NewInt(2), scheme.Params.Q, random)
+
+	for i := 0; i < iterations; i++ {
+		masterSecKey, masterPubKey, err := scheme.generateMasterKeys(samplerQ)
+		if err != nil {
+			return err
+		}
+		x, err := data.NewRandomVector(scheme.Params.L, samplerX)
+		if err != nil {
+			return err
+		}
+		y, err := data.NewRandomVector(scheme.Params.L, samplerY)
+		if err != nil {
+			return err
+		}
+		expected, err := x.Dot(y)
+		if err != nil {
+			return err
+		}
+
+		cipher, err := scheme.Encrypt(x, masterPubKey, WithSampler(samplerQ))
+		if err != nil {
+			return err
+		}
+		key, err := scheme.DeriveKey(masterSecKey, y)
+		if err != nil {
+			return err
+		}
+		got, err := scheme.Decrypt(cipher, key, y)
+		if err != nil || got.Cmp(expected) != 0 {
+			return &InvariantViolation{
+				Iteration: i,
+				X:         x,
+				Y:         y,
+				Expected:  expected,
+				Got:       got,
+				Err:       err,
+			}
+		}
+	}
+
+	return nil
+}
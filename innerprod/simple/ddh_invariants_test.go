@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	mathrand "math/rand"
+	"testing"
+
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_RunInvariantChecks(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.NoError(t, simple.RunInvariantChecks(ddh, 5))
+
+	// a broken parameter set: G does not generate the group of order Q,
+	// so the inner products cannot be found
+	params := *ddh.Params
+	params.G = new(big.Int).Sub(params.P, big.NewInt(1))
+	broken := simple.NewDDHFromParams(&params)
+
+	run := func(seed int64) *simple.InvariantViolation {
+		err := simple.RunInvariantChecksFromReader(broken, 5, mathrand.New(mathrand.NewSource(seed)))
+		var violation *simple.InvariantViolation
+		if !errors.As(err, &violation) {
+			t.Fatalf("expected an invariant violation, got %v", err)
+		}
+		return violation
+	}
+	v1 := run(42)
+	v2 := run(42)
+	assert.Equal(t, v1.Error(), v2.Error(), "checks should be reproducible with the same randomness")
+	assert.Equal(t, 3, len(v1.X))
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// ddhParamsJSON is the JSON form of DDHParams, with the integers
+// encoded as strings of decimal digits.
+type ddhParamsJSON struct {
+	L      int    `json:"l"`
+	Bound  string `json:"bound"`
+	BoundY string `json:"bound_y,omitempty"`
+	G      string `json:"g"`
+	P      string `json:"p"`
+	Q      string `json:"q"`
+	Scale  int    `json:"scale,omitempty"`
+}
+
+// MarshalJSON encodes the parameters as a JSON object with the fields
+// l, bound, bound_y, g, p, q and scale, where all the integers except
+// l and scale are given as strings of decimal digits. bound_y is
+// omitted if BoundY is nil, and scale if it is 0.
+func (params DDHParams) MarshalJSON() ([]byte, error) {
+	if params.Bound == nil || params.G == nil || params.P == nil || params.Q == nil {
+		return nil, fmt.Errorf("parameters should have Bound, G, P and Q")
+	}
+	j := ddhParamsJSON{
+		L:     params.L,
+		Bound: params.Bound.String(),
+		G:     params.G.String(),
+		P:     params.P.String(),
+		Q:     params.Q.String(),
+		Scale: params.Scale,
+	}
+	if params.BoundY != nil {
+		j.BoundY = params.BoundY.String()
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the parameters encoded by MarshalJSON. It
+// returns an error if an integer is malformed or missing, if l is not
+// positive, if P is not greater than 2, or if Q does not divide
+// P - 1.
+func (params *DDHParams) UnmarshalJSON(b []byte) error {
+	var j ddhParamsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.L < 1 {
+		return fmt.Errorf("l should be positive")
+	}
+
+	var ints [4]*big.Int
+	for i, s := range []string{j.Bound, j.G, j.P, j.Q} {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("%q is not a decimal integer", s)
+		}
+		ints[i] = n
+	}
+	var boundY *big.Int
+	if j.BoundY != "" {
+		n, ok := new(big.Int).SetString(j.BoundY, 10)
+		if !ok {
+			return fmt.Errorf("%q is not a decimal integer", j.BoundY)
+		}
+		boundY = n
+	}
+
+	bound, g, p, q := ints[0], ints[1], ints[2], ints[3]
+	if p.Cmp(big.NewInt(2)) <= 0 {
+		return fmt.Errorf("p should be greater than 2")
+	}
+	if q.Sign() <= 0 || new(big.Int).Mod(new(big.Int).Sub(p, big.NewInt(1)), q).Sign() != 0 {
+		return fmt.Errorf("q should divide p - 1")
+	}
+
+	*params = DDHParams{
+		L:      j.L,
+		Bound:  bound,
+		BoundY: boundY,
+		G:      g,
+		P:      p,
+		Q:      q,
+		Scale:  j.Scale,
+	}
+
+	return nil
+}
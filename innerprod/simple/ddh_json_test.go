@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHParamsJSON(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	ddh, err = ddh.WithBoundY(big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during configuration of the bound on y: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	b, err := json.Marshal(ddh.Params)
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	var params simple.DDHParams
+	if err := json.Unmarshal(b, &params); err != nil {
+		t.Fatalf("Error during deserialization: %v", err)
+	}
+	assert.Equal(t, *ddh.Params, params)
+
+	// the same randomness yields the same ciphertext
+	reloaded := simple.NewDDHFromParams(&params)
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	r := simple.WithSampler(&fixedSampler{value: big.NewInt(123456789)})
+	cipher1, err := ddh.Encrypt(x, masterPubKey, r)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	cipher2, err := reloaded.Encrypt(x, masterPubKey, r)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, cipher1, cipher2)
+}
+
+func TestSimple_DDHParamsJSONInvalid(t *testing.T) {
+	for _, s := range []string{
+		`{"l":2,"bound":"10","p":"23","q":"11"}`,
+		`{"l":2,"bound":"10","g":"4","p":"23","q":"0x0b"}`,
+		`{"l":2,"bound":"10","g":"4","p":"23","q":"7"}`,
+		`{"l":0,"bound":"10","g":"4","p":"23","q":"11"}`,
+		`{"l":2,"bound":"10","g":"4","p":"1","q":"11"}`,
+		`{"l":2,"bound":"10","bound_y":"x","g":"4","p":"23","q":"11"}`,
+	} {
+		var params simple.DDHParams
+		assert.Error(t, json.Unmarshal([]byte(s), &params), s)
+	}
+
+	var params simple.DDHParams
+	assert.NoError(t, json.Unmarshal([]byte(`{"l":2,"bound":"10","g":"4","p":"23","q":"11"}`), &params))
+	assert.Nil(t, params.BoundY)
+}
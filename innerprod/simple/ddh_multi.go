@@ -94,7 +94,7 @@ func NewDDHMultiPrecomp(slots, l, modulusLength int, bound *big.Int) (*DDHMulti,
 func NewDDHMultiFromParams(slots int, params *DDHParams) *DDHMulti {
 	return &DDHMulti{
 		Slots: slots,
-		DDH:   &DDH{params},
+		DDH:   &DDH{Params: params},
 	}
 }
 
@@ -108,7 +108,7 @@ func NewDDHMultiFromParams(slots int, params *DDHParams) *DDHMulti {
 // not be properly instantiated.
 func NewDDHMultiClient(params *DDHParams) *DDHMultiClient {
 	return &DDHMultiClient{
-		DDH: &DDH{params},
+		DDH: &DDH{Params: params},
 	}
 }
 
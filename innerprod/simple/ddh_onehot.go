@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// EncryptOneHot encrypts the vector x of length l with x_index = value
+// and all the other coordinates zero, with the provided master public
+// key. The ciphertext is the same as the one produced by Encrypt for x
+// with the same randomness and options, but only a single power of the
+// generator g^value is computed, since g^0 = 1. It returns an error if
+// index is not in [0, l), if the master public key is not of length l,
+// if value is not bounded, or if encryption failed.
+func (d *DDH) EncryptOneHot(index, value int, masterPubKey data.Vector, opts ...EncryptOption) (data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= d.Params.L {
+		return nil, fmt.Errorf("index should be in [0, %d)", d.Params.L)
+	}
+	if err := internal.CheckPubKeyLength(masterPubKey, d.Params.L); err != nil {
+		return nil, err
+	}
+	v := big.NewInt(int64(value))
+	if new(big.Int).Abs(v).Cmp(d.Params.Bound) > 0 {
+		return nil, fmt.Errorf("value should not be greater than bound")
+	}
+
+	gv := internal.ModExp(d.Params.G, v, d.Params.P)
+	return d.encryptWith(d.newEncryptConfig(opts), masterPubKey, func(i int) *big.Int {
+		// ct_i = mpk[i]^r, multiplied by g^value only at index
+		if i != index {
+			return nil
+		}
+		return gv
+	})
+}
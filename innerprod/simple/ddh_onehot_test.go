@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptOneHot(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(4, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3), big.NewInt(1)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	for index := 0; index < 4; index++ {
+		for _, value := range []int{-100, 0, 1, 42} {
+			cipher, err := ddh.EncryptOneHot(index, value, masterPubKey)
+			if err != nil {
+				t.Fatalf("Error during encryption: %v", err)
+			}
+			assert.Equal(t, 5, len(cipher))
+			xy, err := ddh.Decrypt(cipher, key, y)
+			if err != nil {
+				t.Fatalf("Error during decryption: %v", err)
+			}
+			expected := int64(value) * y[index].Int64()
+			assert.Equal(t, expected, xy.Int64(), "obtained incorrect inner product")
+		}
+	}
+
+	_, err = ddh.EncryptOneHot(4, 1, masterPubKey)
+	assert.Error(t, err, "index out of range should be rejected")
+	_, err = ddh.EncryptOneHot(-1, 1, masterPubKey)
+	assert.Error(t, err, "negative index should be rejected")
+	_, err = ddh.EncryptOneHot(0, 101, masterPubKey)
+	assert.Error(t, err, "value out of bound should be rejected")
+	_, err = ddh.EncryptOneHot(0, 1, masterPubKey[:3])
+	assert.Error(t, err, "master public key of wrong length should be rejected")
+
+	// the options of Encrypt apply
+	sampler := &fixedSampler{value: big.NewInt(12345)}
+	cipher, err := ddh.EncryptOneHot(2, -7, masterPubKey, simple.WithSampler(sampler))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(-7), big.NewInt(0)})
+	expected, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, expected, cipher)
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// DecryptPair pairs a ciphertext component ct_i (i >= 1) with the
+// corresponding coordinate y_i of the vector y.
+type DecryptPair struct {
+	Cipher *big.Int
+	Y      *big.Int
+}
+
+// DecryptOnline decrypts the inner product of x and y like Decrypt,
+// but consumes the ciphertext components ct_1, ..., ct_l together with
+// the coordinates of y from the channel pairs as they arrive, instead
+// of requiring the whole ciphertext and y upfront. It accepts the first
+// component ct_0 of the ciphertext and the functional encryption key
+// derived for y.
+//
+// The product of ct_i^y_i is maintained as the pairs arrive; once the
+// channel is closed, it is divided by ct_0^key and the discrete
+// logarithm is computed. The pairs must arrive in the order of
+// coordinates. In case of an error, the remaining pairs are drained
+// until the channel is closed, so that the sender is never blocked.
+// It returns an error if a coordinate of y is out of bound, if the
+// number of pairs is not l, or if the inner product could not be found.
+func (d *DDH) DecryptOnline(ct0, key *big.Int, pairs <-chan DecryptPair) (*big.Int, error) {
+	dec := d.NewStreamDecryptor(ct0, key)
+	for pair := range pairs {
+		if err := dec.Consume(pair.Cipher, pair.Y); err != nil {
+			drain(pairs)
+			return nil, err
+		}
+	}
+
+	return dec.Finish()
+}
+
+// StreamDecryptor decrypts the inner product of x and y consuming the
+// ciphertext components ct_1, ..., ct_l together with the coordinates
+// of y one at a time, see DDH.NewStreamDecryptor.
+type StreamDecryptor struct {
+	d   *DDH
+	ct0 *big.Int
+	key *big.Int
+	// num and denom hold the products of ct_i^y_i for
+	// positive and negative y_i, respectively
+	num   *big.Int
+	denom *big.Int
+	n     int
+}
+
+// NewStreamDecryptor returns a StreamDecryptor for the ciphertext with
+// the first component ct0 and the functional encryption key derived
+// for y. The pairs (ct_i, y_i) are passed to Consume in the order of
+// coordinates, and the inner product is obtained with Finish.
+func (d *DDH) NewStreamDecryptor(ct0, key *big.Int) *StreamDecryptor {
+	return &StreamDecryptor{
+		d:     d,
+		ct0:   ct0,
+		key:   key,
+		num:   big.NewInt(1),
+		denom: big.NewInt(1),
+	}
+}
+
+// Consume consumes the next ciphertext component ct_i together with
+// the coordinate y_i of y. It returns a *data.BoundViolationError with
+// Index i - 1 if y_i is out of bound, and a different error if more
+// than l pairs were consumed.
+func (s *StreamDecryptor) Consume(ct, y *big.Int) error {
+	if s.n >= s.d.Params.L {
+		return internal.ErrMalformedCipher
+	}
+	if err := (data.Vector{y}).CheckBound(s.d.boundY()); err != nil {
+		// the index of y_i in y, not in the single-coordinate vector
+		err.(*data.BoundViolationError).Index = s.n
+		return err
+	}
+
+	// negative powers are collected in the denominator,
+	// so that a single inversion is needed at the end
+	if y.Sign() == -1 {
+		t := new(big.Int).Exp(ct, new(big.Int).Neg(y), s.d.Params.P)
+		s.denom.Mod(s.denom.Mul(s.denom, t), s.d.Params.P)
+	} else {
+		t := new(big.Int).Exp(ct, y, s.d.Params.P)
+		s.num.Mod(s.num.Mul(s.num, t), s.d.Params.P)
+	}
+	s.n++
+
+	return nil
+}
+
+// Consumed returns the number of pairs consumed so far.
+func (s *StreamDecryptor) Consumed() int {
+	return s.n
+}
+
+// Partial returns the product of ct_i^y_i over the pairs consumed so
+// far. It is meant for reporting progress only: the intermediate
+// element is still masked, since ct_0^key is applied only by Finish,
+// so it carries no information about the partial inner product and
+// its discrete logarithm should not be computed.
+func (s *StreamDecryptor) Partial() *big.Int {
+	res := new(big.Int).ModInverse(s.denom, s.d.Params.P)
+	return res.Mod(res.Mul(res, s.num), s.d.Params.P)
+}
+
+// Finish divides the product of ct_i^y_i by ct_0^key and returns the
+// inner product of x and y, the only correct result of decryption.
+// It returns an error if the number of consumed pairs is not l, or if
+// the inner product could not be found.
+func (s *StreamDecryptor) Finish() (*big.Int, error) {
+	if s.n != s.d.Params.L {
+		return nil, internal.ErrMalformedCipher
+	}
+
+	denom := new(big.Int).Exp(s.ct0, s.key, s.d.Params.P)
+	denom.Mod(denom.Mul(denom, s.denom), s.d.Params.P)
+	denom.ModInverse(denom, s.d.Params.P)
+	res := new(big.Int).Mul(s.num, denom)
+
+	return s.d.solveDlog(res.Mod(res, s.d.Params.P))
+}
+
+// drain consumes all the pairs until the channel is closed.
+func drain(pairs <-chan DecryptPair) {
+	for range pairs {
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHDecryptOnline(t *testing.T) {
+	l := 5
+	bound := big.NewInt(1000)
+	ddh, err := simple.NewDDHPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	x, _ := data.NewRandomVector(l, sampler)
+	y, _ := data.NewRandomVector(l, sampler)
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	stream := func(n int, yi func(i int) *big.Int) <-chan simple.DecryptPair {
+		pairs := make(chan simple.DecryptPair)
+		go func() {
+			for i := 0; i < n; i++ {
+				pairs <- simple.DecryptPair{Cipher: cipher[1+i%l], Y: yi(i)}
+			}
+			close(pairs)
+		}()
+		return pairs
+	}
+	coordinate := func(i int) *big.Int { return y[i%l] }
+
+	xy, err := ddh.DecryptOnline(cipher[0], key, stream(l, coordinate))
+	if err != nil {
+		t.Fatalf("Error during online decryption: %v", err)
+	}
+	xyCheck, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(xyCheck), "online decryption should match decryption")
+
+	_, err = ddh.DecryptOnline(cipher[0], key, stream(l-1, coordinate))
+	assert.Error(t, err, "too few pairs should be rejected")
+	_, err = ddh.DecryptOnline(cipher[0], key, stream(l+2, coordinate))
+	assert.Error(t, err, "too many pairs should be rejected")
+	_, err = ddh.DecryptOnline(cipher[0], key, stream(l, func(i int) *big.Int {
+		if i == 2 {
+			return big.NewInt(-1001)
+		}
+		return y[i]
+	}))
+	var boundErr *data.BoundViolationError
+	if assert.True(t, errors.As(err, &boundErr), "coordinates of y out of bound should be rejected") {
+		assert.Equal(t, 2, boundErr.Index)
+		assert.Equal(t, int64(-1001), boundErr.Value.Int64())
+	}
+}
+
+func TestSimple_DDHStreamDecryptor(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	dec := ddh.NewStreamDecryptor(cipher[0], key)
+	assert.Equal(t, int64(1), dec.Partial().Int64())
+	_, err = dec.Finish()
+	assert.Error(t, err, "finishing before all pairs are consumed should fail")
+	for i := range y {
+		if err := dec.Consume(cipher[i+1], y[i]); err != nil {
+			t.Fatalf("Error during consuming pair %d: %v", i, err)
+		}
+		assert.Equal(t, i+1, dec.Consumed())
+	}
+	assert.Error(t, dec.Consume(cipher[1], y[0]), "too many pairs should be rejected")
+
+	// the partial element equals G^<x,y> only once ct_0^key is removed
+	p := ddh.Params.P
+	mask := new(big.Int).Exp(cipher[0], key, p)
+	unmasked := new(big.Int).Mul(dec.Partial(), mask.ModInverse(mask, p))
+	unmasked.Mod(unmasked, p)
+	expected := new(big.Int).Exp(ddh.Params.G, big.NewInt(333), p)
+	expected.ModInverse(expected, p)
+	assert.Equal(t, 0, unmasked.Cmp(expected))
+
+	xy, err := dec.Finish()
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+
+	dec = ddh.NewStreamDecryptor(cipher[0], key)
+	assert.NoError(t, dec.Consume(cipher[1], y[0]))
+	err = dec.Consume(cipher[2], big.NewInt(101))
+	var boundErr *data.BoundViolationError
+	if assert.True(t, errors.As(err, &boundErr), "coordinates of y out of bound should be rejected") {
+		assert.Equal(t, 1, boundErr.Index)
+		assert.Equal(t, int64(100), boundErr.Bound.Int64())
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"io"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// EncryptOption configures a single call of DDH.Encrypt.
+type EncryptOption func(*encryptConfig)
+
+// encryptConfig holds the configuration of a single encryption.
+type encryptConfig struct {
+	// sampler of the randomness r
+	sampler sample.Sampler
+	// whether r is derived deterministically, thus
+	// expected to repeat
+	deterministic bool
+	// whether EncryptBatch uses the same r for all
+	// the vectors of the batch
+	sharedRandomness bool
+	// called every progressEvery coordinates, nil if disabled
+	progress      func(fraction float64)
+	progressEvery int
+}
+
+// WithProgress makes Encrypt call f with the fraction of the
+// coordinates of x encrypted so far, every n coordinates and once all
+// of them are encrypted, e.g. to show the progress of encrypting very
+// long vectors. The ciphertext is the same as without the option. If
+// f is nil or n is not positive, the option has no effect.
+func WithProgress(n int, f func(fraction float64)) EncryptOption {
+	return func(c *encryptConfig) {
+		if f == nil || n <= 0 {
+			return
+		}
+		c.progress = f
+		c.progressEvery = n
+	}
+}
+
+// WithSampler makes Encrypt sample the randomness r with the provided
+// sampler instead of sampling it uniformly from [2, Q). It is meant for
+// testing, e.g. to encrypt with values of r at the edges of the range.
+// Note that the security of the scheme relies on r being uniformly random.
+func WithSampler(s sample.Sampler) EncryptOption {
+	return func(c *encryptConfig) {
+		c.sampler = s
+	}
+}
+
+// WithRand returns a copy of the scheme instance that reads all the
+// randomness of encryption and key generation from random instead of
+// crypto/rand, e.g. to make tests reproducible or to inject faults.
+// With a reader returning a fixed stream, such as one seeded with a
+// fixed seed, the keys and ciphertexts depend only on the stream, and
+// GenerateMasterKeys runs sequentially. The reader must be safe for
+// concurrent use if the copy is used concurrently. It does not affect
+// the noise of DecryptWithDP. A nil reader restores crypto/rand.
+//
+// The security of the scheme relies on the randomness being
+// unpredictable, thus a reader other than crypto/rand should only be
+// used for testing.
+func (d *DDH) WithRand(random io.Reader) *DDH {
+	c := *d
+	c.random = random
+
+	return &c
+}
+
+// uniformSampler returns a sampler of values in [min, max) reading
+// the randomness of the scheme instance.
+func (d *DDH) uniformSampler(min, max *big.Int) sample.Sampler {
+	return sample.NewUniformRangeFromReader(min, max, d.random)
+}
+
+// WithNonceGuard returns a copy of the scheme instance that remembers
+// (hashes of) the randomness r of its last n encryptions, and makes
+// Encrypt return an error if r is ever repeated. Encrypting two vectors
+// with the same r leaks their difference, thus a repeated r signals a
+// broken source of randomness. The guard of the copy is safe for
+// concurrent use; it is not shared with the original instance.
+func (d *DDH) WithNonceGuard(n int) *DDH {
+	c := *d
+	c.nonceGuard = nil
+	if n > 0 {
+		c.nonceGuard = newNonceGuard(n)
+	}
+
+	return &c
+}
+
+// WithSolver returns a copy of the scheme instance that computes the
+// discrete logarithm during decryption with the provided solver, e.g.
+// one delegating the computation to a hardware accelerated service,
+// instead of the default dlog.BabyStepGiantStepSolver. Any type with
+// the method Solve(h, g, P, Q, bound *big.Int) (*big.Int, error)
+// can be used as a solver.
+func (d *DDH) WithSolver(s dlog.Solver) *DDH {
+	c := *d
+	c.solver = s
+	c.tables = nil
+
+	return &c
+}
+
+// WithTableCache returns a copy of the scheme instance that caches the
+// baby-step table of the discrete logarithm computation, so that it is
+// built only at the first decryption and reused afterwards. The cache
+// replaces any solver set with WithSolver. Its counters are available
+// through TableStats.
+func (d *DDH) WithTableCache() *DDH {
+	c := *d
+	c.tables = dlog.NewTableCache()
+	c.solver = dlog.BabyStepGiantStepSolver{Tables: c.tables}
+
+	return &c
+}
+
+// TableStats returns the number of builds, reuses and lookups of the
+// baby-step tables cached by the scheme instance. All counters are zero
+// if the cache was not enabled with WithTableCache.
+func (d *DDH) TableStats() dlog.TableStats {
+	if d.tables == nil {
+		return dlog.TableStats{}
+	}
+
+	return d.tables.Stats()
+}
+
+// WithResultCache returns a copy of the scheme instance that caches
+// up to size inner products obtained by Decrypt, evicting the least
+// recently used ones. Decrypting the same ciphertext with the same
+// derived key and y again then skips the discrete logarithm search.
+// The cache is indexed by SHA-256 hashes of the decryption inputs,
+// but it holds the decrypted inner products in memory. It is safe
+// for concurrent use; it is not shared with the original instance.
+// A size of 0 or less disables the cache.
+func (d *DDH) WithResultCache(size int) *DDH {
+	c := *d
+	c.results = nil
+	if size > 0 {
+		c.results = newResultCache(size)
+	}
+
+	return &c
+}
+
+// Close releases the resources held by the scheme instance: it empties
+// its caches of baby-step tables and of decrypted inner products,
+// forgets the randomness remembered by its nonce guard, and overwrites
+// its convergence key with zeros. Afterwards the methods of the
+// instance that generate keys, encrypt or decrypt return
+// fe.ErrClosed. Copies of the instance obtained by its With methods
+// share these resources: they remain usable and refill the caches,
+// but EncryptConvergent fails on them once the convergence key is
+// destroyed. Calling Close more than once has no effect. It always
+// returns nil.
+func (d *DDH) Close() error {
+	if !atomic.CompareAndSwapUint32(&d.closed, 0, 1) {
+		return nil
+	}
+
+	if d.tables != nil {
+		d.tables.Clear()
+	}
+	if d.results != nil {
+		d.results.clear()
+	}
+	if d.nonceGuard != nil {
+		d.nonceGuard.clear()
+	}
+	if d.convergenceKey != nil {
+		d.convergenceKey.destroy()
+	}
+
+	return nil
+}
+
+// checkOpen returns fe.ErrClosed if the scheme instance was closed.
+func (d *DDH) checkOpen() error {
+	if atomic.LoadUint32(&d.closed) != 0 {
+		return fe.ErrClosed
+	}
+
+	return nil
+}
+
+// newEncryptConfig applies the options to the default configuration
+// of an encryption in the scheme d.
+func (d *DDH) newEncryptConfig(opts []EncryptOption) *encryptConfig {
+	c := &encryptConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.sampler == nil {
+		c.sampler = d.uniformSampler(big.NewInt(2), d.Params.Q)
+	}
+
+	return c
+}
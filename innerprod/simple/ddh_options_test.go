@@ -0,0 +1,477 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/fe"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedSampler always samples the same value.
+type fixedSampler struct {
+	value *big.Int
+}
+
+func (s *fixedSampler) Sample() (*big.Int, error) {
+	return new(big.Int).Set(s.value), nil
+}
+
+func TestSimple_DDHEncryptWithSampler(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// r at both edges of [2, Q)
+	for _, r := range []*big.Int{big.NewInt(2), new(big.Int).Sub(ddh.Params.Q, big.NewInt(1))} {
+		sampler := &fixedSampler{value: r}
+		cipher1, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler))
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		cipher2, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler))
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		assert.Equal(t, cipher1, cipher2, "same randomness should give the same ciphertext")
+		assert.Equal(t, 0, cipher1[0].Cmp(new(big.Int).Exp(ddh.Params.G, r, ddh.Params.P)))
+
+		xy, err := ddh.Decrypt(cipher1, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-333)), "obtained incorrect inner product")
+	}
+}
+
+func TestSimple_DDHWithNonceGuard(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	r1 := simple.WithSampler(&fixedSampler{value: big.NewInt(12345)})
+	r2 := simple.WithSampler(&fixedSampler{value: big.NewInt(54321)})
+
+	guarded := ddh.WithNonceGuard(1)
+	_, err = guarded.Encrypt(x, masterPubKey, r1)
+	assert.NoError(t, err)
+	_, err = guarded.Encrypt(x, masterPubKey, r1)
+	assert.Error(t, err, "reused randomness should be detected")
+
+	// with a guard of size 1, r1 is forgotten after r2 is used
+	_, err = guarded.Encrypt(x, masterPubKey, r2)
+	assert.NoError(t, err)
+	_, err = guarded.Encrypt(x, masterPubKey, r1)
+	assert.NoError(t, err)
+
+	// the original instance is not guarded
+	_, err = ddh.Encrypt(x, masterPubKey, r1)
+	assert.NoError(t, err)
+	_, err = ddh.Encrypt(x, masterPubKey, r1)
+	assert.NoError(t, err)
+
+	guarded = ddh.WithNonceGuard(100)
+	for i := 0; i < 10; i++ {
+		_, err = guarded.Encrypt(x, masterPubKey)
+		assert.NoError(t, err)
+	}
+}
+
+// countingSolver computes discrete logarithms with the default
+// solver and counts its invocations.
+type countingSolver struct {
+	dlog.BabyStepGiantStepSolver
+	calls int
+}
+
+func (s *countingSolver) Solve(h, g, p, q, bound *big.Int) (*big.Int, error) {
+	s.calls++
+	return s.BabyStepGiantStepSolver.Solve(h, g, p, q, bound)
+}
+
+func TestSimple_DDHWithSolver(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	solver := &countingSolver{}
+	xy, err := ddh.WithSolver(solver).Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	assert.Equal(t, 1, solver.calls, "custom solver should be used")
+
+	_, err = ddh.Decrypt(cipher, key, y)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, solver.calls, "original instance should use the default solver")
+
+	xy, err = ddh.WithSolver(solver).DecryptUnbounded(cipher, key, y, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	assert.Equal(t, 2, solver.calls, "custom solver should be used by DecryptUnbounded")
+}
+
+func TestSimple_DDHWithKangarooSolver(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-1000), big.NewInt(999)})
+	y := data.NewVector([]*big.Int{big.NewInt(-998), big.NewInt(-1000)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// the range of inner products is [-2 * 10^6, 2 * 10^6]
+	for _, tableBound := range []*big.Int{nil, big.NewInt(10000000)} {
+		solver := dlog.KangarooSolver{TableBound: tableBound}
+		xy, err := ddh.WithSolver(solver).Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-1000)), "obtained incorrect inner product")
+	}
+}
+
+func TestSimple_DDHWithTableCache(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	cached := ddh.WithTableCache()
+	for i := 0; i < 3; i++ {
+		xy, err := cached.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	}
+
+	stats := cached.TableStats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should be built once")
+	assert.Equal(t, uint64(2), stats.Reuses, "table should be reused")
+	assert.True(t, stats.Lookups > 0, "lookups should be counted")
+	assert.Equal(t, uint64(0), ddh.TableStats().Builds, "original instance should not cache tables")
+
+	// a ciphertext with a zero component is rejected, not a panic
+	malformed := data.NewVector([]*big.Int{big.NewInt(0), cipher[1], cipher[2]})
+	_, err = cached.Decrypt(malformed, key, y)
+	assert.Error(t, err)
+	_, err = cached.DecryptUnbounded(malformed, key, y, big.NewInt(1000))
+	assert.Error(t, err)
+}
+
+func TestSimple_DDHWithResultCache(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher1, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	cipher2, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	solver := &countingSolver{}
+	cached := ddh.WithSolver(solver).WithResultCache(1)
+	for i := 0; i < 3; i++ {
+		xy, err := cached.Decrypt(cipher1, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+		// the caller may modify the result
+		xy.SetInt64(100)
+	}
+	assert.Equal(t, 1, solver.calls, "cached result should be reused")
+
+	// cipher2 evicts cipher1 from the cache of size 1
+	_, err = cached.Decrypt(cipher2, key, y)
+	assert.NoError(t, err)
+	_, err = cached.Decrypt(cipher1, key, y)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, solver.calls, "least recently used result should be evicted")
+
+	// inputs are checked also on a cache hit
+	_, err = cached.Decrypt(cipher1[:2], key, y)
+	assert.Error(t, err)
+	tighter, err := cached.WithBoundY(big.NewInt(4))
+	if err != nil {
+		t.Fatalf("Error during setting the bound of y: %v", err)
+	}
+	_, err = tighter.Decrypt(cipher1, key, y)
+	assert.Error(t, err)
+
+	// a different y misses the cache
+	y2 := data.NewVector([]*big.Int{big.NewInt(5), big.NewInt(4)})
+	key2, err := ddh.DeriveKey(masterSecKey, y2)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := cached.Decrypt(cipher1, key2, y2)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-7)), "obtained incorrect inner product")
+	assert.Equal(t, 4, solver.calls)
+
+	// concurrent use
+	concurrent := ddh.WithResultCache(2)
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			xy, err := concurrent.Decrypt(cipher1, key, y)
+			if err == nil && xy.Cmp(big.NewInt(-2)) != 0 {
+				err = fmt.Errorf("obtained incorrect inner product %s", xy)
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		assert.NoError(t, <-errs)
+	}
+}
+
+func TestSimple_DDHClose(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	scheme := ddh.WithTableCache().WithResultCache(4).WithNonceGuard(4).
+		WithConvergenceKey([]byte("0123456789abcdef0123456789abcdef"))
+	copied := scheme.WithSolver(nil)
+	_, err = scheme.Decrypt(cipher, key, y)
+	assert.NoError(t, err)
+	_, err = copied.EncryptConvergent(x, masterPubKey)
+	assert.NoError(t, err)
+
+	assert.NoError(t, scheme.Close())
+	assert.NoError(t, scheme.Close(), "closing twice should have no effect")
+	assert.Equal(t, uint64(1), scheme.TableStats().Builds, "counters should be kept")
+
+	_, _, err = scheme.GenerateMasterKeys()
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = scheme.DeriveKey(masterSecKey, y)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = scheme.Encrypt(x, masterPubKey)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = scheme.EncryptBatch([]data.Vector{x}, masterPubKey)
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+	_, err = scheme.Decrypt(cipher, key, y)
+	assert.True(t, errors.Is(err, fe.ErrClosed), "cached results should not be served")
+	_, err = scheme.DecryptUnbounded(cipher, key, y, big.NewInt(1000))
+	assert.True(t, errors.Is(err, fe.ErrClosed))
+
+	// copies remain usable, but the shared convergence key is destroyed
+	xy, err := copied.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+	_, err = copied.EncryptConvergent(x, masterPubKey)
+	assert.Error(t, err)
+}
+
+func TestSimple_DDHEncryptWithProgress(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(5, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100), big.NewInt(0), big.NewInt(3)})
+
+	var fractions []float64
+	progress := simple.WithProgress(2, func(f float64) { fractions = append(fractions, f) })
+	sampler := &fixedSampler{value: big.NewInt(12345)}
+	cipher, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler), progress)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, []float64{0.4, 0.8, 1}, fractions)
+
+	// the ciphertext is the same as without the callback
+	expected, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler), simple.WithProgress(0, nil))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, expected, cipher)
+}
+
+// failingReader fails every read with err.
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestSimple_DDHWithRand(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	seed := []byte("fixed seed")
+
+	var secKeys, pubKeys, ciphers []data.Vector
+	for i := 0; i < 2; i++ {
+		seeded := ddh.WithRand(internal.NewDetReader(seed))
+		masterSecKey, masterPubKey, err := seeded.GenerateMasterKeys()
+		if err != nil {
+			t.Fatalf("Error during master key generation: %v", err)
+		}
+		cipher, err := seeded.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		secKeys = append(secKeys, masterSecKey)
+		pubKeys = append(pubKeys, masterPubKey)
+		ciphers = append(ciphers, cipher)
+	}
+	assert.Equal(t, secKeys[0], secKeys[1], "keys should depend only on the seed")
+	assert.Equal(t, pubKeys[0], pubKeys[1], "keys should depend only on the seed")
+	b0, err := data.MarshalVectors(ciphers[:1])
+	assert.NoError(t, err)
+	b1, err := data.MarshalVectors(ciphers[1:])
+	assert.NoError(t, err)
+	assert.Equal(t, b0, b1, "ciphertexts should be byte-identical")
+
+	// the default source of randomness is not affected
+	cipher, err := ddh.Encrypt(x, pubKeys[0])
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.NotEqual(t, ciphers[0], cipher)
+	key, err := ddh.DeriveKey(secKeys[0], x)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.Decrypt(ciphers[0], key, x)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(10401), xy.Int64(), "obtained incorrect inner product")
+
+	// failures of the source are returned
+	errRand := errors.New("injected fault")
+	failing := ddh.WithRand(failingReader{errRand})
+	_, _, err = failing.GenerateMasterKeys()
+	assert.True(t, errors.Is(err, errRand), "expected the injected fault, got %v", err)
+	_, err = failing.Encrypt(x, pubKeys[0])
+	assert.True(t, errors.Is(err, errRand), "expected the injected fault, got %v", err)
+	_, err = failing.WithRand(nil).Encrypt(x, pubKeys[0])
+	assert.NoError(t, err, "nil reader should restore crypto/rand")
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// SelectModulusForLatency returns the smallest modulus length
+// supported by NewDDHPrecomp for which the scheme can be configured
+// with the length of input vectors l and the bound by which
+// coordinates of input vectors are bounded.
+//
+// Decryption time and memory are dominated by the baby-step table
+// of the discrete logarithm search, which holds about
+// 2 * sqrt(l * bound²) entries (positive and negative results are
+// searched separately). The size of the table depends only on l and
+// bound, so an error is returned if it exceeds maxTableEntries,
+// regardless of the modulus. Note that any table that can be built
+// in practice implies l * bound² far below the order of the smallest
+// precomputed group. An error is also returned if no precomputed
+// group is large enough for l and bound.
+func SelectModulusForLatency(l int, bound *big.Int, maxTableEntries uint64) (int, error) {
+	entries := dlogTableEntries(l, bound)
+	if entries.Cmp(new(big.Int).SetUint64(maxTableEntries)) > 0 {
+		return 0, fmt.Errorf("estimated discrete logarithm table of %s entries exceeds %d entries",
+			entries, maxTableEntries)
+	}
+
+	for _, modulusLength := range PrecompModulusLengths() {
+		if _, err := NewDDHPrecomp(l, modulusLength, bound); err == nil {
+			return modulusLength, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no precomputed group is large enough for l = %d and bound %s", l, bound)
+}
+
+// dlogTableEntries estimates the number of entries of the baby-step
+// tables needed to find an inner product within [-l * bound², l * bound²].
+func dlogTableEntries(l int, bound *big.Int) *big.Int {
+	m := new(big.Int).Mul(big.NewInt(int64(l)), new(big.Int).Mul(bound, bound))
+	m.Sqrt(m)
+	m.Add(m, big.NewInt(1))
+
+	return m.Lsh(m, 1)
+}
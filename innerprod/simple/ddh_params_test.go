@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectModulusForLatency(t *testing.T) {
+	// l * bound^2 = 2^20, table of 2 * (2^10 + 1) entries
+	modulusLength, err := simple.SelectModulusForLatency(16, big.NewInt(256), 2050)
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, modulusLength)
+
+	_, err = simple.SelectModulusForLatency(16, big.NewInt(256), 2049)
+	assert.Error(t, err, "table exceeding the limit should be rejected")
+
+	// any table small enough to be built fits into the smallest group
+	bound := new(big.Int).Lsh(big.NewInt(1), 60)
+	modulusLength, err = simple.SelectModulusForLatency(1, bound, ^uint64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, modulusLength)
+}
+
+func TestSimple_DDHFingerprint(t *testing.T) {
+	params := &simple.DDHParams{
+		L:     3,
+		Bound: big.NewInt(1000),
+		G:     big.NewInt(4),
+		P:     big.NewInt(23),
+		Q:     big.NewInt(11),
+	}
+	ddh := simple.NewDDHFromParams(params)
+	// the fingerprint of the fixed parameters must not change
+	assert.Equal(t, "8f7af77866a902eb1dddf11cfe06d52a3010880024565cd570a187fefbb7b24a", ddh.Fingerprint())
+
+	// equal values in a different internal representation
+	p := new(big.Int).Mul(big.NewInt(23), big.NewInt(1<<40))
+	p.Rsh(p, 40)
+	same := *params
+	same.P = p
+	same.BoundY = big.NewInt(1000)
+	assert.Equal(t, ddh.Fingerprint(), simple.NewDDHFromParams(&same).Fingerprint())
+
+	for _, change := range []func(p *simple.DDHParams){
+		func(p *simple.DDHParams) { p.L = 4 },
+		func(p *simple.DDHParams) { p.Bound = big.NewInt(999) },
+		func(p *simple.DDHParams) { p.BoundY = big.NewInt(10) },
+		func(p *simple.DDHParams) { p.G = big.NewInt(2) },
+		func(p *simple.DDHParams) { p.Q = big.NewInt(22) },
+		func(p *simple.DDHParams) { p.Scale = 2 },
+	} {
+		other := *params
+		change(&other)
+		assert.NotEqual(t, ddh.Fingerprint(), simple.NewDDHFromParams(&other).Fingerprint())
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// PEM block types of the master keys of the DDH scheme.
+const (
+	PEMTypeMasterSecretKey = "GOFE DDH MASTER SECRET KEY"
+	PEMTypeMasterPublicKey = "GOFE DDH MASTER PUBLIC KEY"
+)
+
+// pemFingerprintHeader is the header of a PEM block holding the
+// fingerprint of the parameters of the scheme.
+const pemFingerprintHeader = "Params-Fingerprint"
+
+// ExportPEM encodes the master secret or public key in a PEM block of
+// the given type, PEMTypeMasterSecretKey or PEMTypeMasterPublicKey.
+// The key is serialized with data.MarshalVectors, and the block has a
+// Params-Fingerprint header holding Fingerprint of the scheme, which
+// ImportPEM checks. The PEM of a secret key is as sensitive as the key
+// itself. It returns an error if the type is unknown or the key is not
+// of length l.
+func (d *DDH) ExportPEM(blockType string, key data.Vector) ([]byte, error) {
+	if blockType != PEMTypeMasterSecretKey && blockType != PEMTypeMasterPublicKey {
+		return nil, fmt.Errorf("unknown PEM block type %q", blockType)
+	}
+	if len(key) != d.Params.L {
+		return nil, fmt.Errorf("key should be of length %d", d.Params.L)
+	}
+	b, err := data.MarshalVectors([]data.Vector{key})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:    blockType,
+		Headers: map[string]string{pemFingerprintHeader: d.Fingerprint()},
+		Bytes:   b,
+	}), nil
+}
+
+// ImportPEM decodes the master key of the given type from the first
+// PEM block of b, encoded by ExportPEM. It returns an error if there is
+// no PEM block, if the block is of a different type, if it was
+// exported under parameters with a different fingerprint, or if the
+// key is malformed: not of length l, or with components outside of
+// [0, Q) for a secret key or of [1, P) for a public key. Note that the
+// fingerprint covers all the parameters, the bounds included.
+func (d *DDH) ImportPEM(blockType string, b []byte) (data.Vector, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("PEM block is of type %q, expected %q", block.Type, blockType)
+	}
+	fingerprint, ok := block.Headers[pemFingerprintHeader]
+	if !ok {
+		return nil, fmt.Errorf("PEM block has no %s header", pemFingerprintHeader)
+	}
+	if fingerprint != d.Fingerprint() {
+		return nil, fmt.Errorf("key was exported for parameters with fingerprint %s, the scheme has %s",
+			fingerprint, d.Fingerprint())
+	}
+
+	vs, err := data.UnmarshalVectors(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(vs) != 1 || len(vs[0]) != d.Params.L {
+		return nil, fmt.Errorf("key should be a single vector of length %d", d.Params.L)
+	}
+	lo, hi := big.NewInt(0), d.Params.Q
+	if blockType == PEMTypeMasterPublicKey {
+		lo, hi = big.NewInt(1), d.Params.P
+	}
+	for i, c := range vs[0] {
+		if c.Cmp(lo) < 0 || c.Cmp(hi) >= 0 {
+			return nil, fmt.Errorf("component %d of the key is out of range", i)
+		}
+	}
+
+	return vs[0], nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHPEM(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	secPEM, err := ddh.ExportPEM(simple.PEMTypeMasterSecretKey, masterSecKey)
+	if err != nil {
+		t.Fatalf("Error during export: %v", err)
+	}
+	assert.True(t, bytes.HasPrefix(secPEM, []byte("-----BEGIN GOFE DDH MASTER SECRET KEY-----")))
+	pubPEM, err := ddh.ExportPEM(simple.PEMTypeMasterPublicKey, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during export: %v", err)
+	}
+
+	sec, err := ddh.ImportPEM(simple.PEMTypeMasterSecretKey, secPEM)
+	if err != nil {
+		t.Fatalf("Error during import: %v", err)
+	}
+	assert.Equal(t, masterSecKey, sec)
+	pub, err := ddh.ImportPEM(simple.PEMTypeMasterPublicKey, pubPEM)
+	if err != nil {
+		t.Fatalf("Error during import: %v", err)
+	}
+	assert.Equal(t, masterPubKey, pub)
+
+	// the imported keys work together
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(sec, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	cipher, err := ddh.Encrypt(x, pub)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = ddh.ImportPEM(simple.PEMTypeMasterPublicKey, secPEM)
+	assert.Error(t, err, "block of another type should be rejected")
+	_, err = ddh.ImportPEM(simple.PEMTypeMasterSecretKey, []byte("not a PEM"))
+	assert.Error(t, err, "missing block should be rejected")
+	_, err = ddh.ExportPEM("RSA PRIVATE KEY", masterSecKey)
+	assert.Error(t, err, "unknown type should be rejected")
+
+	other, err := simple.NewDDHPrecomp(3, 1536, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, err = other.ImportPEM(simple.PEMTypeMasterSecretKey, secPEM)
+	if assert.Error(t, err, "key of other parameters should be rejected") {
+		assert.Contains(t, err.Error(), other.Fingerprint())
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// CiphertextProof is a non-interactive zero-knowledge proof that a
+// ciphertext (ct_0, ct_1, ..., ct_l) of the DDH scheme is well formed,
+// i.e. that its creator knows r and x such that ct_0 = g^r and
+// ct_i = mpk_i^r * g^x_i with the same r in all the components.
+//
+// It is the Fiat-Shamir transform of the Sigma protocol proving
+// knowledge of r and x: C is the challenge, and Z and W the responses
+// for r and the coordinates of x, respectively.
+type CiphertextProof struct {
+	C *big.Int
+	Z *big.Int
+	W data.Vector
+}
+
+// proofDomain separates the challenges of CiphertextProof from other
+// uses of the hash function.
+const proofDomain = "gofe/simple/DDH/CiphertextProof"
+
+// EncryptWithProof works like Encrypt, but it also returns a proof that
+// the ciphertext is well formed, to be checked with VerifyCiphertext.
+// The proof reveals nothing about x. It returns an error if x is not
+// bounded, or if encryption failed.
+func (d *DDH) EncryptWithProof(x, masterPubKey data.Vector) (data.Vector, *CiphertextProof, error) {
+	if len(x) != len(masterPubKey) {
+		return nil, nil, internal.ErrMalformedInput
+	}
+	sampler := d.uniformSampler(big.NewInt(0), d.Params.Q)
+	r, err := d.uniformSampler(big.NewInt(2), d.Params.Q).Sample()
+	if err != nil {
+		return nil, nil, err
+	}
+	cipher, err := d.Encrypt(x, masterPubKey, WithSampler(&fixedSampler{r}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// commitments a0 = g^a and a_i = mpk_i^a * g^b_i
+	a, err := sampler.Sample()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := data.NewRandomVector(len(x), sampler)
+	if err != nil {
+		return nil, nil, err
+	}
+	commitments := make(data.Vector, len(x)+1)
+	commitments[0] = new(big.Int).Exp(d.Params.G, a, d.Params.P)
+	for i, h := range masterPubKey {
+		commitments[i+1] = internal.MulExp2(h, a, d.Params.G, b[i], d.Params.P)
+	}
+
+	// responses z = a + c * r and w_i = b_i + c * x_i
+	c := d.proofChallenge(cipher, masterPubKey, commitments)
+	z := new(big.Int).Mul(c, r)
+	z.Add(z, a).Mod(z, d.Params.Q)
+	w := make(data.Vector, len(x))
+	for i, xi := range x {
+		w[i] = new(big.Int).Mul(c, xi)
+		w[i].Add(w[i], b[i]).Mod(w[i], d.Params.Q)
+	}
+
+	return cipher, &CiphertextProof{C: c, Z: z, W: w}, nil
+}
+
+// VerifyCiphertext returns true if proof proves that cipher is a well
+// formed ciphertext for the master public key, i.e. that its creator
+// knows r and x such that cipher is the encryption of x with
+// randomness r. All the components are also checked to be elements of
+// the group of order Q.
+//
+// The proof does not show that x is bounded, so decryption of a
+// ciphertext with a valid proof may still fail if the creator
+// encrypted a vector out of bound.
+func (d *DDH) VerifyCiphertext(cipher, masterPubKey data.Vector, proof *CiphertextProof) bool {
+	if proof == nil || proof.C == nil || proof.Z == nil ||
+		len(cipher) != len(masterPubKey)+1 || len(proof.W) != len(masterPubKey) {
+		return false
+	}
+	for _, s := range append(data.Vector{proof.C, proof.Z}, proof.W...) {
+		if s == nil || s.Sign() < 0 || s.Cmp(d.Params.Q) >= 0 {
+			return false
+		}
+	}
+	one := big.NewInt(1)
+	for _, e := range append(append(data.Vector{}, cipher...), masterPubKey...) {
+		if e == nil || e.Sign() <= 0 || e.Cmp(d.Params.P) >= 0 ||
+			new(big.Int).Exp(e, d.Params.Q, d.Params.P).Cmp(one) != 0 {
+			return false
+		}
+	}
+
+	// commitments a0 = g^z * ct_0^-c and a_i = mpk_i^z * g^w_i * ct_i^-c
+	negC := new(big.Int).Sub(d.Params.Q, proof.C)
+	commitments := make(data.Vector, len(cipher))
+	commitments[0] = internal.MulExp2(d.Params.G, proof.Z, cipher[0], negC, d.Params.P)
+	for i, h := range masterPubKey {
+		a := internal.MulExp2(h, proof.Z, d.Params.G, proof.W[i], d.Params.P)
+		a.Mul(a, new(big.Int).Exp(cipher[i+1], negC, d.Params.P))
+		commitments[i+1] = a.Mod(a, d.Params.P)
+	}
+
+	return d.proofChallenge(cipher, masterPubKey, commitments).Cmp(proof.C) == 0
+}
+
+// proofChallenge returns the challenge of CiphertextProof, the hash of
+// the parameters of the scheme, the master public key, the ciphertext
+// and the commitments, reduced modulo Q.
+func (d *DDH) proofChallenge(cipher, masterPubKey, commitments data.Vector) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(proofDomain))
+	write := func(x *big.Int) {
+		b := x.Bytes()
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	for _, x := range []*big.Int{d.Params.P, d.Params.Q, d.Params.G} {
+		write(x)
+	}
+	for _, v := range []data.Vector{masterPubKey, cipher, commitments} {
+		write(big.NewInt(int64(len(v))))
+		for _, x := range v {
+			write(x)
+		}
+	}
+
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, d.Params.Q)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptWithProof(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+
+	cipher, proof, err := ddh.EncryptWithProof(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.True(t, ddh.VerifyCiphertext(cipher, masterPubKey, proof), "valid proof should be accepted")
+
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+
+	// a component replaced by a component of another ciphertext
+	other, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	mixed := append(data.Vector{}, cipher...)
+	mixed[2] = other[2]
+	assert.False(t, ddh.VerifyCiphertext(mixed, masterPubKey, proof), "mixed ciphertext should be rejected")
+	assert.False(t, ddh.VerifyCiphertext(other, masterPubKey, proof), "proof of another ciphertext should be rejected")
+
+	// a ciphertext modified homomorphically
+	shifted := append(data.Vector{}, cipher...)
+	shifted[1] = new(big.Int).Mul(cipher[1], ddh.Params.G)
+	shifted[1].Mod(shifted[1], ddh.Params.P)
+	assert.False(t, ddh.VerifyCiphertext(shifted, masterPubKey, proof), "modified ciphertext should be rejected")
+
+	tampered := *proof
+	tampered.Z = new(big.Int).Add(proof.Z, big.NewInt(1))
+	assert.False(t, ddh.VerifyCiphertext(cipher, masterPubKey, &tampered), "modified proof should be rejected")
+	tampered = *proof
+	tampered.W = proof.W[:2]
+	assert.False(t, ddh.VerifyCiphertext(cipher, masterPubKey, &tampered), "proof of wrong length should be rejected")
+	assert.False(t, ddh.VerifyCiphertext(cipher, masterPubKey, nil), "missing proof should be rejected")
+
+	// an element outside the group of order Q
+	outside := append(data.Vector{}, cipher...)
+	outside[0] = new(big.Int).Sub(ddh.Params.P, big.NewInt(1))
+	assert.False(t, ddh.VerifyCiphertext(outside, masterPubKey, proof), "element outside the group should be rejected")
+}
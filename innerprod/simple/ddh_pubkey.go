@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// CompressPubKey packs the master public key into l big-endian unsigned
+// integers, each padded to the byte length of P, without any length
+// prefixes or signs, i.e. into the format written by
+// GenerateMasterKeysToWriter. It returns an error if the key is not of
+// length l or any of its coordinates is not in [1, P).
+//
+// Since the coordinates are uniformly random group elements, they
+// cannot be compressed below the byte length of P; the packing only
+// saves the per-coordinate overhead of general serializations.
+func (d *DDH) CompressPubKey(masterPubKey data.Vector) ([]byte, error) {
+	if len(masterPubKey) != d.Params.L {
+		return nil, internal.ErrMalformedPubKey
+	}
+	width := (d.Params.P.BitLen() + 7) / 8
+	b := make([]byte, len(masterPubKey)*width)
+	for i, h := range masterPubKey {
+		if h.Sign() <= 0 || h.Cmp(d.Params.P) >= 0 {
+			return nil, fmt.Errorf("coordinate %d of the master public key should be in [1, P)", i)
+		}
+		h.FillBytes(b[i*width : (i+1)*width])
+	}
+
+	return b, nil
+}
+
+// DecompressPubKey unpacks the master public key packed by
+// CompressPubKey. It returns an error if b is not of length l times
+// the byte length of P, or any of the coordinates is not in [1, P).
+func (d *DDH) DecompressPubKey(b []byte) (data.Vector, error) {
+	width := (d.Params.P.BitLen() + 7) / 8
+	if len(b) != d.Params.L*width {
+		return nil, internal.ErrMalformedPubKey
+	}
+	masterPubKey := make(data.Vector, d.Params.L)
+	for i := range masterPubKey {
+		h := new(big.Int).SetBytes(b[i*width : (i+1)*width])
+		if h.Sign() <= 0 || h.Cmp(d.Params.P) >= 0 {
+			return nil, fmt.Errorf("coordinate %d of the master public key should be in [1, P)", i)
+		}
+		masterPubKey[i] = h
+	}
+
+	return masterPubKey, nil
+}
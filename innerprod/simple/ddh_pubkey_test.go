@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHCompressPubKey(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(20, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	b, err := ddh.CompressPubKey(masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during compression: %v", err)
+	}
+	assert.Equal(t, 20*128, len(b))
+	res, err := ddh.DecompressPubKey(b)
+	if err != nil {
+		t.Fatalf("Error during decompression: %v", err)
+	}
+	assert.Equal(t, masterPubKey, res)
+
+	// general serialization needs a sign byte per coordinate and a header
+	serialized, err := data.MarshalVectors([]data.Vector{masterPubKey})
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	assert.True(t, len(b) < len(serialized))
+	t.Logf("compressed: %d bytes, MarshalVectors: %d bytes", len(b), len(serialized))
+
+	// small coordinates are padded
+	small := append(data.Vector{big.NewInt(1)}, masterPubKey[1:]...)
+	b, err = ddh.CompressPubKey(small)
+	if err != nil {
+		t.Fatalf("Error during compression: %v", err)
+	}
+	res, err = ddh.DecompressPubKey(b)
+	if err != nil {
+		t.Fatalf("Error during decompression: %v", err)
+	}
+	assert.Equal(t, int64(1), res[0].Int64())
+
+	_, err = ddh.CompressPubKey(masterPubKey[1:])
+	assert.Error(t, err, "key of wrong length should be rejected")
+	_, err = ddh.CompressPubKey(append(data.Vector{ddh.Params.P}, masterPubKey[1:]...))
+	assert.Error(t, err, "coordinate not in [1, P) should be rejected")
+	_, err = ddh.DecompressPubKey(b[1:])
+	assert.Error(t, err, "truncated key should be rejected")
+	_, err = ddh.DecompressPubKey(make([]byte, len(b)))
+	assert.Error(t, err, "zero coordinate should be rejected")
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+)
+
+// RecoverRandomness returns the randomness r used to produce the
+// ciphertext, i.e. the discrete logarithm of ct0 = G^r, searched in
+// [2, maxR]. Since r is sampled uniformly from a range of the size of
+// the group, recovering it is infeasible for ciphertexts produced with
+// the default sampler, and this method is intended for testing and
+// debugging with samplers of small randomness only, see WithSampler.
+// The search takes time and memory proportional to sqrt(maxR).
+//
+// It returns an error if maxR < 2, if the ciphertext is malformed, or
+// if r is not in the range.
+func (d *DDH) RecoverRandomness(cipher data.Vector, maxR *big.Int) (*big.Int, error) {
+	if err := d.CheckCiphertextShape(cipher); err != nil {
+		return nil, err
+	}
+	r, ok, err := dlog.SearchRange(cipher[0], d.Params.G, d.Params.P, big.NewInt(2), maxR)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("randomness is not in [2, %s]", maxR)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHRecoverRandomness(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	maxR := big.NewInt(100000)
+
+	for _, r := range []int64{2, 3, 4242, 100000} {
+		cipher, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(&fixedSampler{value: big.NewInt(r)}))
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		recovered, err := ddh.RecoverRandomness(cipher, maxR)
+		if err != nil {
+			t.Fatalf("Error during recovery of randomness %d: %v", r, err)
+		}
+		assert.Equal(t, r, recovered.Int64(), "obtained incorrect randomness")
+	}
+
+	// randomness of the default sampler is out of reach
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	_, err = ddh.RecoverRandomness(cipher, maxR)
+	assert.Error(t, err)
+	_, err = ddh.RecoverRandomness(cipher, big.NewInt(1))
+	assert.Error(t, err, "empty range should be rejected")
+	_, err = ddh.RecoverRandomness(cipher[:2], maxR)
+	assert.Error(t, err, "malformed ciphertext should be rejected")
+}
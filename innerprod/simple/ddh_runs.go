@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// Run is a run of Count consecutive coordinates of a vector, all
+// equal to Value, in its run-length encoding.
+type Run struct {
+	Value *big.Int
+	Count int
+}
+
+// EncryptRuns works like Encrypt, but it accepts the input vector x in
+// its run-length encoding, i.e. as a sequence of runs of equal
+// coordinates, without expanding it. The ciphertext is the same as the
+// one produced by Encrypt for the expanded x with the same randomness,
+// but g^Value is computed only once per run. The counts of the runs
+// must be positive and sum up to l. It returns an error if the runs do
+// not encode a vector of length l, if any of the values is not
+// bounded, or if encryption failed.
+func (d *DDH) EncryptRuns(runs []Run, masterPubKey data.Vector, opts ...EncryptOption) (data.Vector, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := internal.CheckPubKeyLength(masterPubKey, d.Params.L); err != nil {
+		return nil, err
+	}
+	n := 0
+	for i, run := range runs {
+		if run.Count <= 0 || run.Count > d.Params.L-n {
+			return nil, fmt.Errorf("runs should encode a vector of length %d", d.Params.L)
+		}
+		n += run.Count
+		if new(big.Int).Abs(run.Value).Cmp(d.Params.Bound) > 0 {
+			return nil, fmt.Errorf("value of run %d should not be greater than bound", i)
+		}
+	}
+	if n != d.Params.L {
+		return nil, fmt.Errorf("runs should encode a vector of length %d", d.Params.L)
+	}
+
+	// g^Value is computed once per run and shared by its coordinates
+	gx := make(data.Vector, 0, d.Params.L)
+	for _, run := range runs {
+		gv := internal.ModExp(d.Params.G, run.Value, d.Params.P)
+		for k := 0; k < run.Count; k++ {
+			gx = append(gx, gv)
+		}
+	}
+
+	return d.encryptWith(d.newEncryptConfig(opts), masterPubKey, func(i int) *big.Int {
+		return gx[i]
+	})
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHEncryptRuns(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(6, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	runs := []simple.Run{
+		{Value: big.NewInt(5), Count: 3},
+		{Value: big.NewInt(-2), Count: 1},
+		{Value: big.NewInt(0), Count: 2},
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(5), big.NewInt(5), big.NewInt(5), big.NewInt(-2), big.NewInt(0), big.NewInt(0)})
+
+	// the ciphertext matches the one of the expanded vector
+	sampler := &fixedSampler{value: big.NewInt(12345)}
+	cipher, err := ddh.EncryptRuns(runs, masterPubKey, simple.WithSampler(sampler))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	expected, err := ddh.Encrypt(x, masterPubKey, simple.WithSampler(sampler))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, expected, cipher)
+
+	y := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5), big.NewInt(6)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err = ddh.EncryptRuns(runs, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(22), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = ddh.EncryptRuns(runs[:2], masterPubKey)
+	assert.Error(t, err, "too short vector should be rejected")
+	_, err = ddh.EncryptRuns(append(runs, simple.Run{Value: big.NewInt(1), Count: 1}), masterPubKey)
+	assert.Error(t, err, "too long vector should be rejected")
+	_, err = ddh.EncryptRuns([]simple.Run{{Value: big.NewInt(1), Count: 7}, {Value: big.NewInt(1), Count: -1}}, masterPubKey)
+	assert.Error(t, err, "negative count should be rejected")
+	_, err = ddh.EncryptRuns([]simple.Run{{Value: big.NewInt(101), Count: 6}}, masterPubKey)
+	assert.Error(t, err, "value out of bound should be rejected")
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+)
+
+// DeriveKeySparse works like DeriveKey, but it accepts y in its sparse
+// representation and only touches the coordinates of the master secret
+// key at the indices of its nonzero coordinates. The key equals the
+// key derived by DeriveKey for y in the dense representation. It
+// returns an error if y is not bounded or any of its indices is not in
+// [0, l).
+func (d *DDH) DeriveKeySparse(masterSecKey data.Vector, y data.SparseVector) (*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := y.CheckBound(d.boundY()); err != nil {
+		return nil, err
+	}
+
+	key, err := y.DotDense(masterSecKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.Mod(key, d.Params.Q), nil
+}
+
+// DecryptSparse works like Decrypt, but it accepts y in its sparse
+// representation, and only exponentiates the components of the
+// ciphertext at the indices of its nonzero coordinates. The result
+// equals the result of Decrypt for y in the dense representation.
+// It returns an error if y is not bounded, any of its indices is not in
+// [0, l), the ciphertext is not of length l + 1, or decryption failed.
+func (d *DDH) DecryptSparse(cipher data.Vector, key *big.Int, y data.SparseVector) (*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := y.CheckBound(d.boundY()); err != nil {
+		return nil, err
+	}
+	if err := y.CheckIndices(d.Params.L); err != nil {
+		return nil, err
+	}
+	if len(cipher) != d.Params.L+1 {
+		return nil, internal.ErrMalformedCipher
+	}
+
+	// r = prod_{y_i != 0} ct_i^y_i / ct_0^key
+	bases := data.Vector{cipher[0]}
+	exps := data.Vector{new(big.Int).Neg(key)}
+	for _, i := range y.Indices() {
+		bases = append(bases, cipher[i+1])
+		exps = append(exps, y[i])
+	}
+
+	return d.solveDlog(internal.ModExpProduct(bases, exps, d.Params.P))
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHSparse(t *testing.T) {
+	l := 50
+	ddh, err := simple.NewDDHPrecomp(l, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewConstantVector(l, big.NewInt(0))
+	for i := range x {
+		x[i].SetInt64(int64(i%7 - 3))
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	y := data.SparseVector{0: big.NewInt(5), 17: big.NewInt(-100), 49: big.NewInt(3)}
+	yDense, err := y.Dense(l)
+	if err != nil {
+		t.Fatalf("Error during expansion of sparse vector: %v", err)
+	}
+	key, err := ddh.DeriveKeySparse(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	keyDense, err := ddh.DeriveKey(masterSecKey, yDense)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, keyDense, key, "sparse and dense keys should match")
+
+	xy, err := ddh.DecryptSparse(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	xyDense, err := ddh.Decrypt(cipher, keyDense, yDense)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	expected, _ := x.Dot(yDense)
+	assert.Equal(t, 0, xy.Cmp(expected), "obtained incorrect inner product")
+	assert.Equal(t, 0, xy.Cmp(xyDense), "sparse and dense decryption should match")
+
+	// empty y
+	key, err = ddh.DeriveKeySparse(masterSecKey, data.SparseVector{})
+	assert.NoError(t, err)
+	xy, err = ddh.DecryptSparse(cipher, key, data.SparseVector{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, xy.Sign())
+
+	_, err = ddh.DeriveKeySparse(masterSecKey, data.SparseVector{l: big.NewInt(1)})
+	assert.Error(t, err, "index out of range should be rejected")
+	_, err = ddh.DecryptSparse(cipher, key, data.SparseVector{-1: big.NewInt(1)})
+	assert.Error(t, err, "index out of range should be rejected")
+	_, err = ddh.DeriveKeySparse(masterSecKey, data.SparseVector{3: big.NewInt(101)})
+	assert.Error(t, err, "unbounded y should be rejected")
+	_, err = ddh.DecryptSparse(cipher[:l], key, y)
+	assert.Error(t, err, "short ciphertext should be rejected")
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal"
+)
+
+// GenerateMasterKeysToWriter generates a pair of master secret key and
+// master public key like GenerateMasterKeys, but instead of returning
+// them it writes them to secW and pubW coordinate by coordinate, so
+// that the keys are never held in memory as a whole. This allows
+// generating keys for very long vectors.
+//
+// The i-th coordinate of the master secret key is written to secW as
+// a big-endian unsigned integer padded to the byte length of Q, and
+// the i-th coordinate of the master public key to pubW as a big-endian
+// unsigned integer padded to the byte length of P. Thus the keys take
+// l times the byte length of Q and P, respectively. Since each
+// coordinate is written with a separate call, the writers should be
+// buffered, e.g. with bufio.Writer.
+//
+// It returns an error if sampling or writing failed, in which case
+// the writers may hold a part of the keys.
+func (d *DDH) GenerateMasterKeysToWriter(secW, pubW io.Writer) error {
+	if err := d.checkOpen(); err != nil {
+		return err
+	}
+
+	sampler := d.uniformSampler(big.NewInt(2), d.Params.Q)
+	sec := make([]byte, (d.Params.Q.BitLen()+7)/8)
+	pub := make([]byte, (d.Params.P.BitLen()+7)/8)
+	for i := 0; i < d.Params.L; i++ {
+		x, err := sampler.Sample()
+		if err != nil {
+			return err
+		}
+		y := internal.ModExp(d.Params.G, x, d.Params.P)
+
+		x.FillBytes(sec)
+		if _, err := secW.Write(sec); err != nil {
+			return err
+		}
+		y.FillBytes(pub)
+		if _, err := pubW.Write(pub); err != nil {
+			return err
+		}
+	}
+	// do not leave the last coordinate of the secret key in memory
+	for i := range sec {
+		sec[i] = 0
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter accepts n writes and fails afterwards.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+// readFixedWidth splits b into integers of width bytes.
+func readFixedWidth(b []byte, width int) data.Vector {
+	v := make(data.Vector, len(b)/width)
+	for i := range v {
+		v[i] = new(big.Int).SetBytes(b[i*width : (i+1)*width])
+	}
+
+	return v
+}
+
+func TestSimple_DDHGenerateMasterKeysToWriter(t *testing.T) {
+	l := 20000
+	ddh, err := simple.NewDDHDeterministic(l, 256, big.NewInt(100), []byte("streaming keygen"))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+
+	var secW, pubW bytes.Buffer
+	if err := ddh.GenerateMasterKeysToWriter(&secW, &pubW); err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	secWidth := (ddh.Params.Q.BitLen() + 7) / 8
+	pubWidth := (ddh.Params.P.BitLen() + 7) / 8
+	assert.Equal(t, l*secWidth, secW.Len())
+	assert.Equal(t, l*pubWidth, pubW.Len())
+
+	masterSecKey := readFixedWidth(secW.Bytes(), secWidth)
+	masterPubKey := readFixedWidth(pubW.Bytes(), pubWidth)
+	for i := 0; i < l; i += 997 {
+		assert.True(t, masterSecKey[i].Cmp(big.NewInt(2)) >= 0 && masterSecKey[i].Cmp(ddh.Params.Q) < 0)
+		assert.Equal(t, 0, new(big.Int).Exp(ddh.Params.G, masterSecKey[i], ddh.Params.P).Cmp(masterPubKey[i]),
+			"public key should match the secret key")
+	}
+
+	// the keys work with the scheme
+	x := data.NewConstantVector(l, big.NewInt(1))
+	y := data.NewConstantVector(l, big.NewInt(0))
+	y[0].SetInt64(3)
+	y[l-1].SetInt64(-5)
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+
+	err = ddh.GenerateMasterKeysToWriter(&bytes.Buffer{}, &failingWriter{n: 10})
+	assert.Error(t, err, "write error should be returned")
+}
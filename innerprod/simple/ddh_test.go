@@ -17,11 +17,19 @@
 package simple_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/dlog"
+	"github.com/fentec-project/gofe/fe"
 	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/internal"
 	"github.com/fentec-project/gofe/sample"
 	"github.com/stretchr/testify/assert"
 )
@@ -105,3 +113,758 @@ func TestSimple_DDH(t *testing.T) {
 		})
 	}
 }
+
+func TestSimple_DDHDeterministic(t *testing.T) {
+	l := 3
+	bound := big.NewInt(1024)
+
+	ddh1, err := simple.NewDDHDeterministic(l, 256, bound, []byte("shared seed"))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	ddh2, err := simple.NewDDHDeterministic(l, 256, bound, []byte("shared seed"))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	ddh3, err := simple.NewDDHDeterministic(l, 256, bound, []byte("another seed"))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+
+	assert.Equal(t, 256, ddh1.Params.P.BitLen())
+	assert.Equal(t, ddh1.Params, ddh2.Params, "same seed should yield same parameters")
+	assert.NotEqual(t, ddh1.Params.P, ddh3.Params.P, "different seeds should yield different parameters")
+	assert.Equal(t, 0, new(big.Int).Exp(ddh1.Params.G, ddh1.Params.Q, ddh1.Params.P).Cmp(big.NewInt(1)),
+		"G should be of order Q")
+}
+
+func TestSimple_DDHFromPrime(t *testing.T) {
+	precomp, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	p := precomp.Params.P
+
+	ddh, err := simple.NewDDHFromPrime(2, p, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 0, ddh.Params.Q.Cmp(precomp.Params.Q), "Q should be (P - 1) / 2")
+	assert.Equal(t, 0, new(big.Int).Exp(ddh.Params.G, ddh.Params.Q, p).Cmp(big.NewInt(1)),
+		"G should be of order Q")
+	assert.NotEqual(t, 0, ddh.Params.G.Cmp(big.NewInt(1)), "G should not be trivial")
+	// G is derived deterministically from P
+	assert.Equal(t, 0, ddh.Params.G.Cmp(big.NewInt(4)), "G should be 2² mod P")
+	again, err := simple.NewDDHFromPrime(2, p, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 0, again.Params.G.Cmp(ddh.Params.G), "the same P should yield the same G")
+
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-3), big.NewInt(2)})
+	y := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-2)), "obtained incorrect inner product")
+
+	// not a prime
+	_, err = simple.NewDDHFromPrime(2, new(big.Int).Add(p, big.NewInt(2)), big.NewInt(10))
+	assert.Error(t, err)
+	// a prime, but not a safe prime
+	_, err = simple.NewDDHFromPrime(2, big.NewInt(13), big.NewInt(1))
+	assert.Error(t, err)
+	_, err = simple.NewDDHFromPrime(2, p, new(big.Int).Lsh(big.NewInt(1), 600))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+}
+
+func TestSimple_DDHPrecompModulusLengths(t *testing.T) {
+	for _, n := range simple.PrecompModulusLengths() {
+		assert.True(t, simple.SupportsPrecomp(n))
+		_, err := simple.NewDDHPrecomp(2, n, big.NewInt(10))
+		assert.NoError(t, err)
+	}
+
+	assert.False(t, simple.SupportsPrecomp(512))
+	_, err := simple.NewDDHPrecomp(2, 512, big.NewInt(10))
+	assert.Error(t, err)
+}
+
+func BenchmarkDDH_Decrypt(b *testing.B) {
+	l := 100
+	bound := big.NewInt(1000)
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+
+	ddh, err := simple.NewDDHPrecomp(l, 4096, bound)
+	if err != nil {
+		b.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		b.Fatalf("Error during master key generation: %v", err)
+	}
+	x, _ := data.NewRandomVector(l, sampler)
+	y, _ := data.NewRandomVector(l, sampler)
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		b.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		b.Fatalf("Error during encryption: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ddh.Decrypt(cipher, key, y); err != nil {
+			b.Fatalf("Error during decryption: %v", err)
+		}
+	}
+}
+
+func TestSimple_DDHDecryptUnbounded(t *testing.T) {
+	ddh, err := simple.NewDDH(2, 128, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	y := data.NewVector([]*big.Int{big.NewInt(-10), big.NewInt(3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	// the encryptor uses a bound larger than the one
+	// assumed by the decryptor
+	params := *ddh.Params
+	params.Bound = big.NewInt(1000)
+	x := data.NewVector([]*big.Int{big.NewInt(300), big.NewInt(-5)})
+	cipher, err := simple.NewDDHFromParams(&params).Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	_, err = ddh.Decrypt(cipher, key, y)
+	assert.Error(t, err, "inner product beyond the bound should not be found")
+
+	xy, err := ddh.DecryptUnbounded(cipher, key, y, big.NewInt(10000))
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-3015)), "obtained incorrect inner product")
+
+	_, err = ddh.DecryptUnbounded(cipher, key, y, big.NewInt(3000))
+	assert.Error(t, err, "inner product beyond max should not be found")
+}
+
+func TestSimple_DDHWithTighterBound(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(1000000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	_, err = ddh.WithTighterBound(big.NewInt(1000001))
+	assert.Error(t, err, "bound should not be loosened")
+	_, err = ddh.WithTighterBound(big.NewInt(0))
+	assert.Error(t, err, "bound should be positive")
+
+	tight, err := ddh.WithTighterBound(big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during bound tightening: %v", err)
+	}
+	assert.Equal(t, 0, ddh.Params.Bound.Cmp(big.NewInt(1000000)), "original bound should not change")
+	assert.Equal(t, 0, tight.Params.Bound.Cmp(big.NewInt(100)))
+
+	x := data.NewVector([]*big.Int{big.NewInt(-100), big.NewInt(42)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(-3)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	xy, err := tight.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-826)), "obtained incorrect inner product")
+}
+
+func TestSimple_DDHBoundTooLarge(t *testing.T) {
+	bound := new(big.Int).Lsh(big.NewInt(1), 600)
+
+	_, err := simple.NewDDHPrecomp(2, 1024, bound)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	_, err = simple.NewDDH(2, 128, bound)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	_, err = simple.NewDDHDeterministic(2, 128, bound, []byte("seed"))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+
+	_, err = simple.NewDDHPrecomp(2, 1000, big.NewInt(10))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, fe.ErrBoundTooLarge), "unrelated error should not be ErrBoundTooLarge")
+}
+
+func TestSimple_DDHCiphertextSize(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 2048, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+
+	size := ddh.CiphertextSize()
+	// header of 4 bytes and 4 components of 257 bytes
+	assert.Equal(t, 4+4*257, size)
+	for i := 0; i < 5; i++ {
+		cipher, err := ddh.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		b, err := data.MarshalVectors([]data.Vector{cipher})
+		if err != nil {
+			t.Fatalf("Error during serialization: %v", err)
+		}
+		assert.True(t, len(b) <= size, "serialized ciphertext should not exceed the size")
+	}
+}
+
+func TestSimple_DDHWithBoundY(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	_, err = ddh.WithBoundY(new(big.Int).Lsh(big.NewInt(1), 1020))
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+
+	weighted, err := ddh.WithBoundY(big.NewInt(1000000))
+	if err != nil {
+		t.Fatalf("Error during setting the bound of y: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-10), big.NewInt(7)})
+	y := data.NewVector([]*big.Int{big.NewInt(1000000), big.NewInt(-999999)})
+
+	_, err = ddh.DeriveKey(masterSecKey, y)
+	assert.Error(t, err, "y beyond the bound should be rejected")
+	key, err := weighted.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+
+	cipher, err := weighted.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	_, err = weighted.Encrypt(data.NewVector([]*big.Int{big.NewInt(11), big.NewInt(0)}), masterPubKey)
+	assert.Error(t, err, "x should still be bounded by the bound")
+
+	xy, err := weighted.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, 0, xy.Cmp(big.NewInt(-16999993)), "obtained incorrect inner product")
+}
+
+func TestSimple_DDHBoundViolation(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	inBound := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	outOfBound := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(-11)})
+	cipher, err := ddh.Encrypt(inBound, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	_, errEncrypt := ddh.Encrypt(outOfBound, masterPubKey)
+	_, errDeriveKey := ddh.DeriveKey(masterSecKey, outOfBound)
+	_, errDecrypt := ddh.Decrypt(cipher, big.NewInt(1), outOfBound)
+	for _, err := range []error{errEncrypt, errDeriveKey, errDecrypt} {
+		var violation *data.BoundViolationError
+		if !errors.As(err, &violation) {
+			t.Fatalf("expected a bound violation, got %v", err)
+		}
+		assert.Equal(t, 2, violation.Index)
+		assert.Equal(t, 0, violation.Value.Cmp(big.NewInt(-11)))
+	}
+}
+
+func TestSimple_DDHDeriveKeyMaximalY(t *testing.T) {
+	l := 5000
+	bound := new(big.Int).Lsh(big.NewInt(1), 100)
+	ddh, err := simple.NewDDHDeterministic(l, 256, bound, []byte("maximal y"))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, _, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	y := data.NewConstantVector(l, bound)
+	for i := 0; i < l; i += 2 {
+		y[i] = new(big.Int).Neg(bound)
+	}
+
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	expected, _ := masterSecKey.Dot(y)
+	expected.Mod(expected, ddh.Params.Q)
+	assert.Equal(t, 0, expected.Cmp(key), "key should match the full precision computation")
+}
+
+func TestSimple_DDHValidateInput(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	valid := data.NewVector([]*big.Int{big.NewInt(-10), big.NewInt(0), big.NewInt(10)})
+	assert.NoError(t, ddh.ValidateInput(valid))
+	_, err = ddh.Encrypt(valid, masterPubKey)
+	assert.NoError(t, err, "validated input should encrypt")
+
+	assert.Error(t, ddh.ValidateInput(valid[:2]), "short vector should be rejected")
+	assert.Error(t, ddh.ValidateInput(append(valid.Copy(), big.NewInt(1))), "long vector should be rejected")
+
+	err = ddh.ValidateInput(data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(11), big.NewInt(0)}))
+	var violation *data.BoundViolationError
+	assert.True(t, errors.As(err, &violation), "unbounded vector should be rejected")
+	assert.Equal(t, 1, violation.Index)
+}
+
+func TestSimple_NewDDHBits(t *testing.T) {
+	ddh, err := simple.NewDDHBits(3, 512, 20)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 0, ddh.Params.Bound.Cmp(big.NewInt(1<<20)))
+
+	// 2 * 3 * 2^(2b) <= 2^510 for b up to 253
+	ddh, err = simple.NewDDHBits(3, 512, 253)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 254, ddh.Params.Bound.BitLen())
+
+	_, err = simple.NewDDHBits(3, 512, 254)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge))
+	assert.Contains(t, err.Error(), "at most 253 bits")
+	_, err = simple.NewDDHBits(4, 512, 254)
+	assert.Contains(t, err.Error(), "at most 253 bits")
+	_, err = simple.NewDDHBits(9, 512, 254)
+	assert.Contains(t, err.Error(), "at most 252 bits")
+	_, err = simple.NewDDHBits(1, 2, 0)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge))
+
+	_, err = simple.NewDDHBits(3, 512, -1)
+	assert.Error(t, err)
+	_, err = simple.NewDDHBits(0, 512, 10)
+	assert.Error(t, err)
+}
+
+func TestSimple_DDHSecurityBits(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 2048, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 112, ddh.SecurityBits())
+	assert.NoError(t, ddh.CheckSecurity())
+
+	ddh, err = simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, 80, ddh.SecurityBits())
+	err = ddh.CheckSecurity()
+	assert.True(t, errors.Is(err, fe.ErrWeakParams), "expected ErrWeakParams, got %v", err)
+}
+
+func TestSimple_DDHDecryptDeadline(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	xy, err := ddh.DecryptDeadline(cipher, key, y, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy.Int64(), "obtained incorrect inner product")
+
+	_, err = ddh.DecryptDeadline(cipher, key, y, time.Now().Add(-time.Second))
+	var timeout *dlog.TimeoutError
+	assert.True(t, errors.As(err, &timeout), "expected *dlog.TimeoutError, got %v", err)
+}
+
+func TestSimple_DDHDecryptSigned(t *testing.T) {
+	l := 3
+	bound := big.NewInt(100)
+	ddh, err := simple.NewDDHPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+
+	constant := func(c int64) data.Vector {
+		v := make(data.Vector, l)
+		for i := range v {
+			v[i] = big.NewInt(c)
+		}
+		return v
+	}
+	// the edges of the signed range [-l * bound², l * bound²]
+	pairs := [][2]data.Vector{
+		{constant(100), constant(-100)},
+		{constant(-100), constant(100)},
+		{constant(-100), constant(-100)},
+		{constant(100), constant(100)},
+		{constant(0), constant(-100)},
+	}
+	// random signed vectors
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	for i := 0; i < 20; i++ {
+		x, _ := data.NewRandomVector(l, sampler)
+		y, _ := data.NewRandomVector(l, sampler)
+		pairs = append(pairs, [2]data.Vector{x, y})
+	}
+
+	for _, pair := range pairs {
+		x, y := pair[0], pair[1]
+		key, err := ddh.DeriveKey(masterSecKey, y)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		cipher, err := ddh.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		xy, err := ddh.DecryptSigned(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption of x = %v, y = %v: %v", x, y, err)
+		}
+		expected, _ := x.Dot(y)
+		assert.Equal(t, 0, xy.Cmp(expected), "obtained incorrect inner product for x = %v, y = %v", x, y)
+	}
+}
+
+func TestSimple_DDHCheckCiphertextShape(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.NoError(t, ddh.CheckCiphertextShape(cipher))
+
+	assert.Error(t, ddh.CheckCiphertextShape(append(cipher.Copy(), big.NewInt(1))), "ciphertext should have l + 1 components")
+	malformed := cipher.Copy()
+	malformed[0] = new(big.Int).Add(ddh.Params.P, big.NewInt(1))
+	assert.Error(t, ddh.CheckCiphertextShape(malformed), "components should be smaller than P")
+	malformed[0] = big.NewInt(-1)
+	assert.Error(t, ddh.CheckCiphertextShape(malformed), "components should be positive")
+}
+
+func TestSimple_NewDDHForSecurityLevel(t *testing.T) {
+	for _, level := range []int{112, 128} {
+		ddh, err := simple.NewDDHForSecurityLevel(2, level, big.NewInt(10))
+		if err != nil {
+			t.Fatalf("Error during simple inner product creation: %v", err)
+		}
+		assert.Equal(t, level, ddh.SecurityBits())
+	}
+
+	for _, level := range []int{80, 100, 192} {
+		_, err := simple.NewDDHForSecurityLevel(2, level, big.NewInt(10))
+		assert.Error(t, err, "level %d should be rejected", level)
+	}
+}
+
+// constSolver returns a fixed result regardless of its input.
+type constSolver struct {
+	res *big.Int
+}
+
+func (s constSolver) Solve(h, g, p, q, bound *big.Int) (*big.Int, error) {
+	return new(big.Int).Set(s.res), nil
+}
+
+func TestSimple_DDHDecryptInt64(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(3, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-20), big.NewInt(100)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(2), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	xy, err := ddh.DecryptInt64(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-333), xy)
+
+	// results at the edges of int64, as returned by a solver
+	minInt64 := new(big.Int).Lsh(big.NewInt(-1), 63)
+	maxInt64 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 63), big.NewInt(1))
+	for _, c := range []struct {
+		res      *big.Int
+		overflow bool
+	}{
+		{minInt64, false},
+		{maxInt64, false},
+		{new(big.Int).Sub(minInt64, big.NewInt(1)), true},
+		{new(big.Int).Add(maxInt64, big.NewInt(1)), true},
+	} {
+		xy, err := ddh.WithSolver(constSolver{c.res}).DecryptInt64(cipher, key, y)
+		if c.overflow {
+			assert.True(t, errors.Is(err, fe.ErrOverflow), "%s should overflow", c.res)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, c.res.Int64(), xy)
+	}
+}
+
+func benchmarkDDHGenerateMasterKeys(b *testing.B, procs int) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+	ddh, err := simple.NewDDHPrecomp(10000, 1024, big.NewInt(10))
+	if err != nil {
+		b.Fatalf("Error during simple inner product creation: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ddh.GenerateMasterKeys(); err != nil {
+			b.Fatalf("Error during master key generation: %v", err)
+		}
+	}
+}
+
+func BenchmarkDDH_GenerateMasterKeysSerial(b *testing.B) {
+	benchmarkDDHGenerateMasterKeys(b, 1)
+}
+
+func BenchmarkDDH_GenerateMasterKeysParallel(b *testing.B) {
+	benchmarkDDHGenerateMasterKeys(b, runtime.NumCPU())
+}
+
+func TestSimple_DDHDecryptWithContext(t *testing.T) {
+	ddh, err := simple.NewDDHPrecomp(2, 1024, big.NewInt(1<<21))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(1 << 21)})
+	y := data.NewVector([]*big.Int{big.NewInt(7), big.NewInt(-3)})
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// the search is cancelled while the range of 2^43 is searched
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = ddh.DecryptWithContext(ctx, cipher, key, y)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "decryption should stop promptly")
+
+	// already cancelled
+	_, err = ddh.DecryptWithContext(ctx, cipher, key, y)
+	assert.Equal(t, context.Canceled, err)
+
+	// the inner product is found before the deadline with a smaller bound
+	ddh, err = simple.NewDDHPrecomp(2, 1024, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err = ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	key, err = ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	x = data.NewVector([]*big.Int{big.NewInt(-5), big.NewInt(2)})
+	cipher, err = ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	xy, err := ddh.DecryptWithContext(ctx, cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, int64(-41), xy.Int64(), "obtained incorrect inner product")
+}
+
+func TestSimple_DDHEncryptLengths(t *testing.T) {
+	l := 3
+	ddh, err := simple.NewDDHPrecomp(l, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	_, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	sampler := sample.NewUniformRange(big.NewInt(-10), big.NewInt(11))
+
+	// every combination of lengths up to 2l, none of them may panic
+	for xLen := 0; xLen <= 2*l; xLen++ {
+		for keyLen := 0; keyLen <= 2*l; keyLen++ {
+			x, _ := data.NewRandomVector(xLen, sampler)
+			mpk := append(data.Vector{}, masterPubKey...)
+			for len(mpk) < keyLen {
+				mpk = append(mpk, masterPubKey[0])
+			}
+			mpk = mpk[:keyLen]
+
+			cipher, err := ddh.Encrypt(x, mpk)
+			switch {
+			case keyLen != l:
+				assert.True(t, errors.Is(err, internal.ErrMalformedPubKey),
+					"x of length %d, key of length %d: expected malformed public key, got %v", xLen, keyLen, err)
+			case xLen != l:
+				assert.True(t, errors.Is(err, internal.ErrMalformedInput),
+					"x of length %d, key of length %d: expected malformed input, got %v", xLen, keyLen, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, ddh.CiphertextSize() > 0, len(cipher) > l)
+			}
+		}
+	}
+
+	_, err = ddh.Encrypt(nil, nil)
+	assert.True(t, errors.Is(err, internal.ErrMalformedPubKey))
+}
+
+func TestSimple_DDHDecryptLengths(t *testing.T) {
+	l := 3
+	ddh, err := simple.NewDDHPrecomp(l, 1024, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Error during scheme creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	x := data.NewConstantVector(l, big.NewInt(2))
+	y := data.NewConstantVector(l, big.NewInt(3))
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+
+	// truncated and extended ciphertexts must be rejected, not panic
+	for n := 0; n <= 2*len(cipher); n++ {
+		if n == len(cipher) {
+			continue
+		}
+		c := append(data.Vector{}, cipher...)
+		for len(c) < n {
+			c = append(c, cipher[0])
+		}
+		_, err := ddh.Decrypt(c[:n], key, y)
+		assert.True(t, errors.Is(err, internal.ErrMalformedCipher),
+			"ciphertext of length %d: expected malformed ciphertext, got %v", n, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("got %d components, expected %d", n, l+1))
+	}
+
+	_, err = ddh.Decrypt(cipher, key, y[:l-1])
+	assert.True(t, errors.Is(err, internal.ErrMalformedInput), "expected malformed input, got %v", err)
+
+	xy, err := ddh.Decrypt(cipher, key, y)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(18), xy)
+}
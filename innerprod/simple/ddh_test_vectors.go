@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/sample"
+)
+
+// Parameters of the scheme used by GenerateTestVectors.
+const (
+	testVectorsL             = 4
+	testVectorsModulusLength = 1024
+	testVectorsBound         = 1000
+)
+
+// TestVectorSet is a test vector of the DDH scheme, meant for checking
+// that other implementations of the scheme agree with gofe. Serialized
+// with encoding/json it is an object with the following fields, where
+// all the integers except l are given as strings of decimal digits,
+// possibly with a leading minus sign, and vectors as arrays of such
+// strings:
+//
+//	scheme             "ddh"
+//	seed               the seed, hex encoded
+//	l                  length of the vectors, a JSON number
+//	bound              bound on the coordinates of x and y
+//	p, q, g            the group: modulus p = 2q + 1 and generator g
+//	                   of the subgroup of order q
+//	master_secret_key  s, with s_i in [2, q)
+//	master_public_key  g^s_i mod p
+//	x, y               the vectors, with coordinates in [-bound, bound]
+//	derived_key        <s, y> mod q
+//	randomness         r in [2, q)
+//	ciphertext         g^r mod p, followed by mpk_i^r * g^x_i mod p
+//	inner_product      <x, y>
+type TestVectorSet struct {
+	Scheme       string   `json:"scheme"`
+	Seed         string   `json:"seed"`
+	L            int      `json:"l"`
+	Bound        string   `json:"bound"`
+	P            string   `json:"p"`
+	Q            string   `json:"q"`
+	G            string   `json:"g"`
+	MasterSecKey []string `json:"master_secret_key"`
+	MasterPubKey []string `json:"master_public_key"`
+	X            []string `json:"x"`
+	Y            []string `json:"y"`
+	DerivedKey   string   `json:"derived_key"`
+	Randomness   string   `json:"randomness"`
+	Ciphertext   []string `json:"ciphertext"`
+	InnerProduct string   `json:"inner_product"`
+}
+
+// GenerateTestVectors returns a test vector of the DDH scheme with
+// vectors of length 4, coordinates bounded by 1000 and the precomputed
+// 1024-bit group of NewDDHPrecomp. The master secret key, x, y and the
+// randomness of the encryption are derived from the seed, thus the
+// result is fully determined by the seed. The test vectors are not
+// secure and must only be used for testing.
+func GenerateTestVectors(seed []byte) TestVectorSet {
+	ddh, err := NewDDHPrecomp(testVectorsL, testVectorsModulusLength, big.NewInt(testVectorsBound))
+	if err != nil {
+		// the parameters are fixed and valid
+		panic(err)
+	}
+	random := internal.NewDetReader(seed)
+	scalars := sample.NewUniformRangeFromReader(big.NewInt(2), ddh.Params.Q, random)
+	bound := ddh.Params.Bound
+	coordinates := sample.NewUniformRangeFromReader(new(big.Int).Neg(bound),
+		new(big.Int).Add(bound, big.NewInt(1)), random)
+
+	// none of the steps can fail, since the deterministic reader
+	// never fails and the inputs are within the bounds
+	masterSecKey, masterPubKey, err := ddh.generateMasterKeys(scalars)
+	if err != nil {
+		panic(err)
+	}
+	x, err := data.NewRandomVector(testVectorsL, coordinates)
+	if err != nil {
+		panic(err)
+	}
+	y, err := data.NewRandomVector(testVectorsL, coordinates)
+	if err != nil {
+		panic(err)
+	}
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		panic(err)
+	}
+	r, err := scalars.Sample()
+	if err != nil {
+		panic(err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey, WithSampler(&fixedSampler{r}))
+	if err != nil {
+		panic(err)
+	}
+	xy, err := x.Dot(y)
+	if err != nil {
+		panic(err)
+	}
+
+	return TestVectorSet{
+		Scheme:       "ddh",
+		Seed:         hex.EncodeToString(seed),
+		L:            testVectorsL,
+		Bound:        bound.String(),
+		P:            ddh.Params.P.String(),
+		Q:            ddh.Params.Q.String(),
+		G:            ddh.Params.G.String(),
+		MasterSecKey: vectorStrings(masterSecKey),
+		MasterPubKey: vectorStrings(masterPubKey),
+		X:            vectorStrings(x),
+		Y:            vectorStrings(y),
+		DerivedKey:   key.String(),
+		Randomness:   r.String(),
+		Ciphertext:   vectorStrings(cipher),
+		InnerProduct: xy.String(),
+	}
+}
+
+// fixedSampler always samples the same value.
+type fixedSampler struct {
+	value *big.Int
+}
+
+func (s *fixedSampler) Sample() (*big.Int, error) {
+	return new(big.Int).Set(s.value), nil
+}
+
+// vectorStrings returns the decimal representations of the
+// coordinates of v.
+func vectorStrings(v data.Vector) []string {
+	s := make([]string, len(v))
+	for i, c := range v {
+		s[i] = c.String()
+	}
+
+	return s
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/stretchr/testify/assert"
+)
+
+// parseVector parses the decimal coordinates of a vector.
+func parseVector(t *testing.T, s []string) data.Vector {
+	v := make(data.Vector, len(s))
+	for i, c := range s {
+		var ok bool
+		v[i], ok = new(big.Int).SetString(c, 10)
+		if !ok {
+			t.Fatalf("invalid integer %q", c)
+		}
+	}
+
+	return v
+}
+
+func TestSimple_GenerateTestVectors(t *testing.T) {
+	set := simple.GenerateTestVectors([]byte("gofe test vectors"))
+	assert.Equal(t, set, simple.GenerateTestVectors([]byte("gofe test vectors")),
+		"test vectors should be determined by the seed")
+	other := simple.GenerateTestVectors([]byte("other seed"))
+	assert.NotEqual(t, set.X, other.X)
+	assert.NotEqual(t, set.Ciphertext, other.Ciphertext)
+
+	// the test vectors agree with the scheme
+	ddh, err := simple.NewDDHPrecomp(set.L, 1024, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	assert.Equal(t, ddh.Params.P.String(), set.P)
+	assert.Equal(t, ddh.Params.Q.String(), set.Q)
+	assert.Equal(t, ddh.Params.G.String(), set.G)
+	x := parseVector(t, set.X)
+	y := parseVector(t, set.Y)
+	assert.NoError(t, x.CheckBound(ddh.Params.Bound))
+	assert.NoError(t, y.CheckBound(ddh.Params.Bound))
+
+	r, _ := new(big.Int).SetString(set.Randomness, 10)
+	cipher, err := ddh.Encrypt(x, parseVector(t, set.MasterPubKey), simple.WithSampler(&fixedSampler{value: r}))
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	assert.Equal(t, parseVector(t, set.Ciphertext), cipher)
+	key, err := ddh.DeriveKey(parseVector(t, set.MasterSecKey), y)
+	if err != nil {
+		t.Fatalf("Error during key derivation: %v", err)
+	}
+	assert.Equal(t, set.DerivedKey, key.String())
+	xy, err := ddh.Decrypt(cipher, key, y)
+	if err != nil {
+		t.Fatalf("Error during decryption: %v", err)
+	}
+	assert.Equal(t, set.InnerProduct, xy.String())
+
+	// documented JSON format
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Error during serialization: %v", err)
+	}
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	for _, name := range []string{"scheme", "seed", "l", "bound", "p", "q", "g", "master_secret_key",
+		"master_public_key", "x", "y", "derived_key", "randomness", "ciphertext", "inner_product"} {
+		assert.Contains(t, fields, name)
+	}
+	assert.Equal(t, "ddh", fields["scheme"])
+	assert.Equal(t, float64(4), fields["l"])
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/dlog"
+)
+
+// DDHWorkspace holds the baby-step table of the discrete logarithm
+// and the temporaries of decryption with DecryptWorkspace, so that
+// repeated decryptions do not allocate memory in the steady state:
+// once the temporaries have grown to the size of the group elements
+// in the first decryptions, the following ones allocate nothing.
+// A workspace is not safe for concurrent use; each goroutine should
+// use its own.
+type DDHWorkspace struct {
+	p, g  *big.Int
+	bound int64
+	table *dlog.Table
+	ws    internal.Workspace
+	// accumulators of the numerator and denominator of g^<x,y>, the
+	// element and its inverse searched in the table
+	num, den, inv, h, hInv big.Int
+	res                    big.Int
+}
+
+// NewWorkspace returns a workspace for decryption with
+// DecryptWorkspace, computing the baby-step table for the whole
+// range of inner products, which takes time and memory proportional
+// to sqrt(l * bound * boundY). It returns an error if the range is too
+// large for a table.
+func (d *DDH) NewWorkspace() (*DDHWorkspace, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	bound := d.dlogBound()
+	table, err := dlog.NewTable(d.Params.P, d.Params.G, bound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DDHWorkspace{
+		p:     d.Params.P,
+		g:     d.Params.G,
+		bound: bound.Int64(),
+		table: table,
+	}, nil
+}
+
+// DecryptWorkspace works like Decrypt, using the baby-step table and
+// the temporaries of ws, thus it does not allocate memory once ws is
+// warmed up, except when it returns an error. The exponentiations are
+// computed with plain square-and-multiply, which is slower than the
+// one of big.Int used by Decrypt; the gain is the absence of garbage.
+//
+// The result is owned by ws and is only valid until the next call
+// with the same workspace; it should be copied to be kept. It returns
+// an error if ws was created for a different group, if the inputs are
+// malformed or not bounded, or if decryption failed.
+func (d *DDH) DecryptWorkspace(ws *DDHWorkspace, cipher data.Vector, key *big.Int, y data.Vector) (*big.Int, error) {
+	if err := d.checkOpen(); err != nil {
+		return nil, err
+	}
+	if ws.p.Cmp(d.Params.P) != 0 || ws.g.Cmp(d.Params.G) != 0 {
+		return nil, fmt.Errorf("workspace was created for a different group")
+	}
+	if err := internal.CheckCiphertextShape(cipher, d.Params.L+1, d.Params.P); err != nil {
+		return nil, err
+	}
+	if len(y) != d.Params.L {
+		return nil, internal.ErrMalformedInput
+	}
+	boundY := d.boundY()
+	for _, yi := range y {
+		if yi.CmpAbs(boundY) > 0 {
+			// CheckBound allocates, thus it is only called to
+			// describe the violation
+			return nil, y.CheckBound(boundY)
+		}
+	}
+
+	// g^<x,y> = prod_i ct_i^y_i / ct_0^key, where the powers with
+	// positive and negative exponents are accumulated separately
+	ws.accumulate(&ws.num, cipher, key, y, 1)
+	ws.accumulate(&ws.den, cipher, key, y, -1)
+	// with t = (num * den)^-1, h = num² * t and h^-1 = den² * t need a
+	// single inversion
+	ws.ws.MulMod(&ws.h, &ws.num, &ws.den, ws.p)
+	if !ws.ws.ModInverse(&ws.inv, &ws.h, ws.p) {
+		return nil, internal.ErrMalformedCipher
+	}
+	ws.ws.MulMod(&ws.h, &ws.num, &ws.num, ws.p)
+	ws.ws.MulMod(&ws.h, &ws.h, &ws.inv, ws.p)
+	ws.ws.MulMod(&ws.hInv, &ws.den, &ws.den, ws.p)
+	ws.ws.MulMod(&ws.hInv, &ws.hInv, &ws.inv, ws.p)
+
+	x, ok := dlog.SolveWorkspace(ws.table, &ws.ws, &ws.h, &ws.hInv, ws.bound)
+	if !ok {
+		return nil, fmt.Errorf("failed to find the discrete logarithm within bound %d", ws.bound)
+	}
+
+	return ws.res.SetInt64(x), nil
+}
+
+// accumulate sets z to the product of the powers ct_i^|e_i| with the
+// sign of e_i equal to sign, where e_0 = -key and e_i = y_i for i > 0,
+// computed with a simultaneous square-and-multiply over the bits of
+// the exponents.
+func (ws *DDHWorkspace) accumulate(z *big.Int, cipher data.Vector, key *big.Int, y data.Vector, sign int) {
+	exp := func(i int) (*big.Int, bool) {
+		if i == 0 {
+			return key, -key.Sign() == sign
+		}
+		return y[i-1], y[i-1].Sign() == sign
+	}
+
+	n := 0
+	for i := range cipher {
+		if e, ok := exp(i); ok && e.BitLen() > n {
+			n = e.BitLen()
+		}
+	}
+	z.SetInt64(1)
+	for j := n - 1; j >= 0; j-- {
+		ws.ws.MulMod(z, z, z, ws.p)
+		for i, c := range cipher {
+			if e, ok := exp(i); ok && absBit(e, j) == 1 {
+				ws.ws.MulMod(z, z, c, ws.p)
+			}
+		}
+	}
+}
+
+// absBit returns the j-th bit of |x|.
+func absBit(x *big.Int, j int) big.Word {
+	words := x.Bits()
+	w := j / bits.UintSize
+	if w >= len(words) {
+		return 0
+	}
+
+	return (words[w] >> (uint(j) % bits.UintSize)) & 1
+}
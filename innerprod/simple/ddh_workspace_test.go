@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple_DDHDecryptWorkspace(t *testing.T) {
+	l := 5
+	bound := big.NewInt(100)
+	ddh, err := simple.NewDDHPrecomp(l, 1024, bound)
+	if err != nil {
+		t.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("Error during master key generation: %v", err)
+	}
+	ws, err := ddh.NewWorkspace()
+	if err != nil {
+		t.Fatalf("Error during workspace creation: %v", err)
+	}
+
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+	edge := data.NewConstantVector(l, bound)
+	pairs := [][2]data.Vector{{edge, edge}, {edge, edge.Neg()}}
+	for i := 0; i < 10; i++ {
+		x, _ := data.NewRandomVector(l, sampler)
+		y, _ := data.NewRandomVector(l, sampler)
+		pairs = append(pairs, [2]data.Vector{x, y})
+	}
+	for _, pair := range pairs {
+		x, y := pair[0], pair[1]
+		key, err := ddh.DeriveKey(masterSecKey, y)
+		if err != nil {
+			t.Fatalf("Error during key derivation: %v", err)
+		}
+		cipher, err := ddh.Encrypt(x, masterPubKey)
+		if err != nil {
+			t.Fatalf("Error during encryption: %v", err)
+		}
+		expected, err := ddh.Decrypt(cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption: %v", err)
+		}
+		xy, err := ddh.DecryptWorkspace(ws, cipher, key, y)
+		if err != nil {
+			t.Fatalf("Error during decryption with workspace: %v", err)
+		}
+		assert.Equal(t, 0, xy.Cmp(expected), "obtained incorrect inner product")
+	}
+
+	y := data.NewConstantVector(l, big.NewInt(101))
+	cipher, err := ddh.Encrypt(edge, masterPubKey)
+	if err != nil {
+		t.Fatalf("Error during encryption: %v", err)
+	}
+	_, err = ddh.DecryptWorkspace(ws, cipher, big.NewInt(1), y)
+	assert.Error(t, err, "unbounded y should be rejected")
+	_, err = ddh.DecryptWorkspace(ws, cipher[:l], big.NewInt(1), edge)
+	assert.Error(t, err, "malformed ciphertext should be rejected")
+}
+
+func TestSimple_DDHDecryptWorkspaceAllocs(t *testing.T) {
+	ddh, x, y, key, cipher := setupWorkspaceDecrypt(t, 20, 1024)
+	ws, err := ddh.NewWorkspace()
+	if err != nil {
+		t.Fatalf("Error during workspace creation: %v", err)
+	}
+	expected, _ := x.Dot(y)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		xy, err := ddh.DecryptWorkspace(ws, cipher, key, y)
+		if err != nil || xy.Cmp(expected) != 0 {
+			t.Fatalf("Error during decryption with workspace: %v", err)
+		}
+	})
+	assert.Equal(t, 0.0, allocs, "decryption with a warmed up workspace should not allocate")
+}
+
+func setupWorkspaceDecrypt(tb testing.TB, l, modulusLength int) (*simple.DDH, data.Vector, data.Vector, *big.Int, data.Vector) {
+	bound := big.NewInt(1000)
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+	ddh, err := simple.NewDDHPrecomp(l, modulusLength, bound)
+	if err != nil {
+		tb.Fatalf("Error during simple inner product creation: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		tb.Fatalf("Error during master key generation: %v", err)
+	}
+	x, _ := data.NewRandomVector(l, sampler)
+	y, _ := data.NewRandomVector(l, sampler)
+	key, err := ddh.DeriveKey(masterSecKey, y)
+	if err != nil {
+		tb.Fatalf("Error during key derivation: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		tb.Fatalf("Error during encryption: %v", err)
+	}
+
+	return ddh, x, y, key, cipher
+}
+
+func BenchmarkDDH_DecryptWorkspace(b *testing.B) {
+	ddh, _, y, key, cipher := setupWorkspaceDecrypt(b, 20, 1024)
+	ws, err := ddh.NewWorkspace()
+	if err != nil {
+		b.Fatalf("Error during workspace creation: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ddh.DecryptWorkspace(ws, cipher, key, y); err != nil {
+			b.Fatalf("Error during decryption: %v", err)
+		}
+	}
+}
+
+func BenchmarkDDH_DecryptAllocating(b *testing.B) {
+	ddh, _, y, key, cipher := setupWorkspaceDecrypt(b, 20, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ddh.Decrypt(cipher, key, y); err != nil {
+			b.Fatalf("Error during decryption: %v", err)
+		}
+	}
+}
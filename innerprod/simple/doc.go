@@ -25,6 +25,21 @@
 // The reference scheme is public key, which means that no master secret
 // key is required for the encryption.
 //
+// The schemes do not protect the integrity of ciphertexts. Since
+// anyone holding the master public key can encrypt any vector, and
+// the ciphertexts are malleable (see DDH.SubtractPublic), a modified
+// ciphertext, together with any checksum computed from the master
+// public key, is indistinguishable from a fresh one. Applications
+// that need to detect tampering should authenticate the ciphertexts,
+// e.g. by signing them.
+//
+// The schemes do not hide the vector y of a functional encryption
+// key: decryption requires y in the clear, and the key itself is a
+// single element <s, y> of Z_q, which reveals nothing about y beyond
+// what the decryptor is given anyway. Applications that need to hide
+// y from the decryptor should use a function-hiding scheme, e.g.
+// fullysec.FHIPE.
+//
 // For instantiation from the decisional Diffie-Hellman assumption
 // (DDH), see struct DDH (and its multi-input variant DDHMulti, which
 // is a secret key scheme, because a part of the secret key is required
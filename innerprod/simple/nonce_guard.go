@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// nonceGuard remembers hashes of the most recently used encryption
+// randomness and detects its reuse.
+type nonceGuard struct {
+	mu     sync.Mutex
+	seen   map[[sha256.Size]byte]bool
+	recent [][sha256.Size]byte
+	next   int
+}
+
+func newNonceGuard(n int) *nonceGuard {
+	return &nonceGuard{
+		seen:   make(map[[sha256.Size]byte]bool, n),
+		recent: make([][sha256.Size]byte, 0, n),
+	}
+}
+
+// check returns an error if r is among the remembered values,
+// otherwise it remembers r, forgetting the oldest value if the
+// guard is full.
+func (g *nonceGuard) check(r *big.Int) error {
+	h := sha256.Sum256(r.Bytes())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.seen[h] {
+		return fmt.Errorf("encryption randomness was reused, the source of randomness is broken")
+	}
+
+	if len(g.recent) < cap(g.recent) {
+		g.recent = append(g.recent, h)
+	} else {
+		delete(g.seen, g.recent[g.next])
+		g.recent[g.next] = h
+		g.next = (g.next + 1) % len(g.recent)
+	}
+	g.seen[h] = true
+
+	return nil
+}
+
+// clear forgets all the remembered values.
+func (g *nonceGuard) clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.seen = make(map[[sha256.Size]byte]bool)
+	g.recent = g.recent[:0]
+	g.next = 0
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// resultCache remembers the most recently decrypted inner products,
+// indexed by a hash of the decryption inputs, and evicts the least
+// recently used ones.
+type resultCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of *resultEntry, most recently used first
+	items map[[sha256.Size]byte]*list.Element
+}
+
+type resultEntry struct {
+	key   [sha256.Size]byte
+	value *big.Int
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[[sha256.Size]byte]*list.Element, size),
+	}
+}
+
+// resultCacheKey hashes the ciphertext, the derived key and y of a
+// decryption. Only the hash is stored, so the index of the cache
+// reveals neither the inputs nor the inner product.
+func resultCacheKey(cipher data.Vector, key *big.Int, y data.Vector) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte("gofe/simple.DDH result cache"))
+	write := func(v data.Vector) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(v)))
+		h.Write(n[:])
+		for _, c := range v {
+			// sign and length prefixed, so that different
+			// inputs cannot hash the same
+			b := c.Bytes()
+			n[0] = byte(c.Sign() + 1)
+			binary.BigEndian.PutUint32(n[1:5], uint32(len(b)))
+			h.Write(n[:5])
+			h.Write(b)
+		}
+	}
+	write(cipher)
+	write(data.Vector{key})
+	write(y)
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// get returns a copy of the cached value for k, if present.
+func (c *resultCache) get(k [sha256.Size]byte) (*big.Int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[k]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+
+	return new(big.Int).Set(e.Value.(*resultEntry).value), true
+}
+
+// put caches a copy of the value v for k, evicting the least
+// recently used value if the cache is full.
+func (c *resultCache) put(k [sha256.Size]byte, v *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[k]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*resultEntry).key)
+	}
+	c.items[k] = c.order.PushFront(&resultEntry{key: k, value: new(big.Int).Set(v)})
+}
+
+// clear removes all the cached values, overwriting them with zeros.
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		e.Value.(*resultEntry).value.SetInt64(0)
+	}
+	c.order.Init()
+	c.items = make(map[[sha256.Size]byte]*list.Element)
+}
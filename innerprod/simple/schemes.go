@@ -0,0 +1,26 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simple
+
+import "github.com/fentec-project/gofe/fe"
+
+func init() {
+	fe.Register(fe.SchemeInfo{Name: "simple.DDH", Assumption: fe.DDH, ModulusLengths: PrecompModulusLengths()})
+	fe.Register(fe.SchemeInfo{Name: "simple.DDHMulti", Assumption: fe.DDH, ModulusLengths: PrecompModulusLengths()})
+	fe.Register(fe.SchemeInfo{Name: "simple.LWE", Assumption: fe.LWE})
+	fe.Register(fe.SchemeInfo{Name: "simple.RingLWE", Assumption: fe.RingLWE})
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/fe"
+)
+
+// CheckOrderBound checks the precondition 2 * n * boundX * boundY <= q
+// of the schemes that recover an inner product of n coordinates,
+// bounded by boundX and boundY, by computing a discrete logarithm in
+// a group of order q. It returns an error wrapping fe.ErrBoundTooLarge,
+// with the actual values, if the precondition does not hold.
+func CheckOrderBound(n int, boundX, boundY, q *big.Int) error {
+	prod := new(big.Int).Mul(boundX, boundY)
+	prod.Mul(prod, big.NewInt(int64(2*n)))
+	if prod.Cmp(q) > 0 {
+		return fmt.Errorf("%w: 2 * %d * %s * %s = %s, group order is %s",
+			fe.ErrBoundTooLarge, n, boundX, boundY, prod, q)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/fe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrderBound(t *testing.T) {
+	q := big.NewInt(1000)
+
+	// 2 * 5 * 10 * 10 = 1000
+	assert.NoError(t, CheckOrderBound(5, big.NewInt(10), big.NewInt(10), q))
+	assert.NoError(t, CheckOrderBound(5, big.NewInt(20), big.NewInt(5), q))
+
+	err := CheckOrderBound(5, big.NewInt(10), big.NewInt(11), q)
+	assert.True(t, errors.Is(err, fe.ErrBoundTooLarge), "expected ErrBoundTooLarge, got %v", err)
+	assert.Contains(t, err.Error(), "1100")
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// detReader is a deterministic stream of pseudo-random bytes,
+// obtained by hashing a seed together with a block counter.
+type detReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// NewDetReader returns a reader producing an endless deterministic
+// stream of pseudo-random bytes, determined by the provided seed.
+// The i-th block of the stream is SHA-256(seed || i), where i is
+// encoded as 8 big-endian bytes.
+func NewDetReader(seed []byte) io.Reader {
+	return &detReader{
+		seed: append([]byte{}, seed...),
+	}
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (r *detReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			ctr := make([]byte, 8)
+			binary.BigEndian.PutUint64(ctr, r.counter)
+			r.counter++
+			block := sha256.Sum256(append(append([]byte{}, r.seed...), ctr...))
+			r.buf = block[:]
+		}
+		k := copy(p[n:], r.buf)
+		r.buf = r.buf[k:]
+		n += k
+	}
+
+	return n, nil
+}
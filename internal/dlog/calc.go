@@ -46,6 +46,13 @@ type CalcZp struct {
 	bound *big.Int
 	m     *big.Int
 	neg   bool
+	// order of the generator, nil if it is not known
+	order *big.Int
+	// cache of baby-step tables, nil if tables are not cached
+	tables *TableCache
+	// whether Solve uses the kangaroo method instead of
+	// the baby-step giant-step method
+	kangaroo bool
 }
 
 // InZp builds parameters needed to calculate a discrete
@@ -74,6 +81,7 @@ func (*Calc) InZp(p, order *big.Int) (*CalcZp, error) {
 		bound: bound,
 		m:     m,
 		neg:   false,
+		order: order,
 	}, nil
 }
 
@@ -84,10 +92,13 @@ func (c *CalcZp) WithBound(bound *big.Int) *CalcZp {
 		m.Add(m, big.NewInt(1))
 
 		return &CalcZp{
-			bound: bound,
-			m:     m,
-			p:     c.p,
-			neg:   c.neg,
+			bound:    bound,
+			m:        m,
+			p:        c.p,
+			neg:      c.neg,
+			tables:   c.tables,
+			order:    c.order,
+			kangaroo: c.kangaroo,
 		}
 	}
 	return c
@@ -97,21 +108,70 @@ func (c *CalcZp) WithBound(bound *big.Int) *CalcZp {
 // negative integers.
 func (c *CalcZp) WithNeg() *CalcZp {
 	return &CalcZp{
-		bound: c.bound,
-		m:     c.m,
-		p:     c.p,
-		neg:   true,
+		bound:    c.bound,
+		m:        c.m,
+		p:        c.p,
+		neg:      true,
+		tables:   c.tables,
+		order:    c.order,
+		kangaroo: c.kangaroo,
 	}
 }
 
+// WithTableCache sets that the baby steps should be taken from the
+// cache, and computed and stored in the cache only if they are not
+// there yet. This saves time when computing many discrete logarithms
+// with the same group, generator and bound. The cache is used only
+// if the bound is smaller than MaxBound.
+func (c *CalcZp) WithTableCache(cache *TableCache) *CalcZp {
+	return &CalcZp{
+		bound:    c.bound,
+		m:        c.m,
+		p:        c.p,
+		neg:      c.neg,
+		tables:   cache,
+		order:    c.order,
+		kangaroo: c.kangaroo,
+	}
+}
+
+// PrecomputeBabySteps returns a copy of the calculator holding the
+// baby steps of generator g for its bound, computed in advance, which
+// are then reused by every call of BabyStepGiantStep with g instead of
+// being recomputed. The steps are stored in the table cache of the
+// calculator, or in a new one if no cache was set with
+// WithTableCache. It returns an error if the bound is not smaller than
+// MaxBound, thus WithBound should be called first.
+func (c *CalcZp) PrecomputeBabySteps(g *big.Int) (*CalcZp, error) {
+	cache := c.tables
+	if cache == nil {
+		cache = NewTableCache()
+	}
+	if err := cache.Precompute(c.p, g, c.bound); err != nil {
+		return nil, err
+	}
+
+	return c.WithTableCache(cache), nil
+}
+
 // BabyStepGiantStep uses the baby-step giant-step method to
 // compute the discrete logarithm in the Zp group. If c.neg is
 // set to true it searches for the answer within [-bound, bound].
 // It does so by running two goroutines, one for negative
 // answers and one for positive. If c.neg is set to false
 // only one goroutine is started, searching for the answer
-// within [0, bound].
+// within [0, bound]. If a table cache is set, the search runs
+// in the calling goroutine using the cached baby steps.
+//
+// If the order of g was given to InZp and the interval contains
+// several solutions, the result does not depend on which of them is
+// found first: the solution with the smallest absolute value is
+// returned, and the positive one if there are two such solutions.
 func (c *CalcZp) BabyStepGiantStep(h, g *big.Int) (*big.Int, error) {
+	if c.tables != nil && c.bound.Cmp(MaxBound) < 0 {
+		return c.canonical(h, g)(c.tables.get(c.p, g, c.m).search(h, c.bound, c.neg))
+	}
+
 	// create goroutines calculating positive and possibly negative
 	// result if c.neg is set to true
 	retChan := make(chan *big.Int)
@@ -141,7 +201,43 @@ func (c *CalcZp) BabyStepGiantStep(h, g *big.Int) (*big.Int, error) {
 		ret.Neg(ret)
 	}
 
-	return ret, nil
+	return c.canonical(h, g)(ret, nil)
+}
+
+// canonical returns a function mapping a solution x of g^x = h to
+// the solution with the smallest absolute value among the solutions
+// x + k * order, preferring the positive one, or to a non-negative
+// solution if c.neg is not set. Since it is not larger in absolute
+// value than x, it is within the bound as well. If the order is not
+// known, err is not nil, or the order turns out not to be a multiple
+// of the order of g, x and err are returned unchanged.
+func (c *CalcZp) canonical(h, g *big.Int) func(x *big.Int, err error) (*big.Int, error) {
+	return func(x *big.Int, err error) (*big.Int, error) {
+		if err != nil || c.order == nil {
+			return x, err
+		}
+
+		r := new(big.Int).Mod(x, c.order)
+		if c.neg {
+			// r - order if it is closer to zero than r
+			rNeg := new(big.Int).Sub(r, c.order)
+			if new(big.Int).Abs(rNeg).Cmp(r) < 0 {
+				r = rNeg
+			}
+		}
+		if r.Cmp(x) == 0 {
+			return x, nil
+		}
+		gr := new(big.Int).Exp(g, new(big.Int).Abs(r), c.p)
+		if r.Sign() < 0 {
+			gr.ModInverse(gr, c.p)
+		}
+		if gr.Cmp(h) != 0 {
+			return x, nil
+		}
+
+		return r, nil
+	}
 }
 
 // runBabyStepGiantStep implements the baby-step giant-step method to
@@ -107,3 +107,52 @@ func TestCalcBN256_BabyStepGiantStep(t *testing.T) {
 	}
 	assert.Equal(t, xCheck.Cmp(x), 0, "BabyStepGiantStep in BN256 returns wrong dlog")
 }
+
+func TestCalcZp_BabyStepGiantStep_TieBreak(t *testing.T) {
+	// 5 generates Z_23*, of order 22
+	p := big.NewInt(23)
+	g := big.NewInt(5)
+	order := big.NewInt(22)
+	calc, err := NewCalc().InZp(p, order)
+	if err != nil {
+		t.Fatalf("Error in creation of calculator: %v", err)
+	}
+	cache := NewTableCache()
+
+	for _, c := range []struct {
+		x        int64
+		bound    int64
+		expected int64
+	}{
+		// 11 and -11 collide, the positive one is chosen
+		{11, 11, 11},
+		{-11, 11, 11},
+		// 3, -19 and 25 collide, the one closest to zero is chosen
+		{-19, 30, 3},
+		{25, 30, 3},
+		// 19 and -3 collide
+		{19, 20, -3},
+	} {
+		h := internal.ModExp(g, big.NewInt(c.x), p)
+		for i := 0; i < 20; i++ {
+			res, err := calc.WithBound(big.NewInt(c.bound)).WithNeg().BabyStepGiantStep(h, g)
+			if err != nil {
+				t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+			}
+			assert.Equal(t, c.expected, res.Int64(), "x = %d", c.x)
+			res, err = calc.WithBound(big.NewInt(c.bound)).WithNeg().WithTableCache(cache).BabyStepGiantStep(h, g)
+			if err != nil {
+				t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+			}
+			assert.Equal(t, c.expected, res.Int64(), "x = %d with table cache", c.x)
+		}
+	}
+
+	// without negative values the smallest non-negative is chosen
+	h := internal.ModExp(g, big.NewInt(25), p)
+	res, err := calc.WithBound(big.NewInt(30)).BabyStepGiantStep(h, g)
+	if err != nil {
+		t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+	}
+	assert.Equal(t, int64(3), res.Int64())
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/fentec-project/gofe/internal"
+)
+
+// kangarooAttempts is the number of walks with different jump
+// functions that the kangaroo method makes before it concludes that
+// there is no solution within the bound. A walk misses an existing
+// solution with a probability of a few percent, thus all the attempts
+// miss it with a negligible probability.
+const kangarooAttempts = 8
+
+// WithKangaroo sets that Solve should use Pollard's kangaroo (lambda)
+// method instead of the baby-step giant-step method. The kangaroo
+// method needs memory independent of the bound, at the cost of more
+// group operations, and can thus be used with bounds for which the
+// table of baby steps does not fit into memory.
+func (c *CalcZp) WithKangaroo() *CalcZp {
+	return &CalcZp{
+		bound:    c.bound,
+		m:        c.m,
+		p:        c.p,
+		neg:      c.neg,
+		tables:   c.tables,
+		order:    c.order,
+		kangaroo: true,
+	}
+}
+
+// Solve computes the discrete logarithm of h with respect to g in the
+// Zp group within the bound of the calculator, using the kangaroo
+// method if WithKangaroo was set and the baby-step giant-step method
+// otherwise. The result is the same with both methods.
+func (c *CalcZp) Solve(h, g *big.Int) (*big.Int, error) {
+	if !c.kangaroo {
+		return c.BabyStepGiantStep(h, g)
+	}
+
+	return c.canonical(h, g)(c.runKangaroo(h, g))
+}
+
+// runKangaroo implements Pollard's kangaroo method to compute the
+// discrete logarithm in the Zp group. It searches for x within
+// [0, bound], or within [-bound, bound] if c.neg is set, where
+// h = g^x mod p, thus it searches for x + bound within [0, n] for
+// n = 2 * bound in the latter case.
+//
+// A tame kangaroo starts at g^n and makes a fixed number of jumps,
+// each of length 2^i for i chosen by a hash of its position. A wild
+// kangaroo then starts at h and jumps with the same rule until it
+// either lands where the tame one stopped, which gives x, or gets past
+// it. Once the paths of both meet, they coincide, thus it is enough to
+// store the final position of the tame kangaroo. If the wild one gets
+// past it, the walks are repeated with a different hash.
+func (c *CalcZp) runKangaroo(h, g *big.Int) (*big.Int, error) {
+	if c.bound.Cmp(MaxBound) >= 0 {
+		return nil, fmt.Errorf("bound should be smaller than %s for the kangaroo method", MaxBound)
+	}
+	lo := int64(0)
+	if c.neg {
+		lo = -c.bound.Int64()
+	}
+	n := c.bound.Int64() - lo
+	// the logarithm of h * g^-lo is x - lo, which is in [0, n]
+	hShift := new(big.Int).Exp(g, big.NewInt(-lo), c.p)
+	hShift.Mod(hShift.Mul(hShift, h), c.p)
+
+	// jumps g^(2^i) for i < k, with the mean length (2^k - 1) / k
+	// being about sqrt(n) / 2
+	sqrtN := int64(math.Sqrt(float64(n))) + 1
+	k := 1
+	for ((int64(1)<<k)-1)/int64(k) < sqrtN/2 {
+		k++
+	}
+	jumps := make([]*big.Int, k)
+	jumps[0] = new(big.Int).Mod(g, c.p)
+	for i := 1; i < k; i++ {
+		jumps[i] = new(big.Int).Mul(jumps[i-1], jumps[i-1])
+		jumps[i].Mod(jumps[i], c.p)
+	}
+
+	// the walks do not allocate memory, thus the memory used does not
+	// depend on the bound
+	var ws internal.Workspace
+	gN := new(big.Int).Exp(g, big.NewInt(n), c.p)
+	tame := new(big.Int)
+	wild := new(big.Int)
+	for attempt := uint64(0); attempt < kangarooAttempts; attempt++ {
+		salt := attempt * 0x9e3779b97f4a7c15
+		jump := func(y *big.Int) int {
+			var w uint64
+			if b := y.Bits(); len(b) > 0 {
+				w = uint64(b[0])
+			}
+			return int((w + salt) % uint64(k))
+		}
+
+		// the tame kangaroo travels about n
+		tame.Set(gN)
+		tameDist := int64(0)
+		for i := int64(0); i < 2*sqrtN; i++ {
+			j := jump(tame)
+			tameDist += int64(1) << j
+			ws.MulMod(tame, tame, jumps[j], c.p)
+		}
+
+		wild.Set(hShift)
+		for wildDist := int64(0); wildDist <= n+tameDist; {
+			if wild.Cmp(tame) == 0 {
+				x := n + tameDist - wildDist
+				if x > n {
+					// a solution larger than the bound
+					break
+				}
+				return big.NewInt(x + lo), nil
+			}
+			j := jump(wild)
+			wildDist += int64(1) << j
+			ws.MulMod(wild, wild, jumps[j], c.p)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find the discrete logarithm within bound " + c.bound.String())
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcZp_Kangaroo(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+	calc, err := NewCalc().InZp(params.p, params.order)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+
+	for _, b := range []int64{1, 2, 10, 1000, 100000} {
+		bound := big.NewInt(b)
+		bsgs := calc.WithNeg().WithBound(bound)
+		kangaroo := bsgs.WithKangaroo()
+		sampler := sample.NewUniformRange(new(big.Int).Neg(bound), new(big.Int).Add(bound, big.NewInt(1)))
+		xs := []*big.Int{new(big.Int).Neg(bound), big.NewInt(0), bound}
+		for i := 0; i < 20; i++ {
+			x, err := sampler.Sample()
+			if err != nil {
+				t.Fatalf("Error during random int generation: %v", err)
+			}
+			xs = append(xs, x)
+		}
+
+		for _, x := range xs {
+			h := internal.ModExp(params.g, x, params.p)
+			expected, err := bsgs.Solve(h, params.g)
+			if err != nil {
+				t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+			}
+			res, err := kangaroo.Solve(h, params.g)
+			if err != nil {
+				t.Fatalf("Error in kangaroo algorithm: %v", err)
+			}
+			assert.Equal(t, 0, res.Cmp(expected), "kangaroo result differs from BabyStepGiantStep")
+			assert.Equal(t, 0, res.Cmp(x), "kangaroo result is wrong")
+		}
+
+		// values outside of the bound are not found
+		h := internal.ModExp(params.g, new(big.Int).Add(bound, big.NewInt(1)), params.p)
+		_, err = kangaroo.Solve(h, params.g)
+		assert.Error(t, err)
+	}
+
+	// without negative values
+	kangaroo := calc.WithBound(big.NewInt(1000)).WithKangaroo()
+	h := internal.ModExp(params.g, big.NewInt(999), params.p)
+	res, err := kangaroo.Solve(h, params.g)
+	if err != nil {
+		t.Fatalf("Error in kangaroo algorithm: %v", err)
+	}
+	assert.Equal(t, int64(999), res.Int64())
+	h = internal.ModExp(params.g, big.NewInt(-1), params.p)
+	_, err = kangaroo.Solve(h, params.g)
+	assert.Error(t, err)
+
+	// the bound of the group order is too large for the kangaroo method
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+	calc, err = NewCalc().InZp(key.P, key.Q)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+	_, err = calc.WithKangaroo().Solve(key.G, key.G)
+	assert.Error(t, err)
+}
+
+// benchmarkCalcZpSolve computes discrete logarithms within [-2^28, 2^28]
+// in a group with a 2048-bit modulus with a calculator without a table
+// cache, reporting the memory allocated by each computation.
+func benchmarkCalcZpSolve(b *testing.B, kangaroo bool) {
+	key, err := keygen.NewPrecompGroup(2048)
+	if err != nil {
+		b.Fatalf("Error during parameters generation: %v", err)
+	}
+	bound := big.NewInt(1 << 28)
+	calc, err := NewCalc().InZp(key.P, key.Q)
+	if err != nil {
+		b.Fatal("Error in creation of new CalcZp:", err)
+	}
+	calc = calc.WithNeg().WithBound(bound)
+	if kangaroo {
+		calc = calc.WithKangaroo()
+	}
+	sampler := sample.NewUniformRange(new(big.Int).Neg(bound), bound)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		x, _ := sampler.Sample()
+		h := internal.ModExp(key.G, x, key.P)
+		b.StartTimer()
+		if _, err := calc.Solve(h, key.G); err != nil {
+			b.Fatalf("Error during computation of discrete logarithm: %v", err)
+		}
+	}
+}
+
+func BenchmarkCalcZp_SolveBabyStepGiantStep(b *testing.B) {
+	benchmarkCalcZpSolve(b, false)
+}
+
+func BenchmarkCalcZp_SolveKangaroo(b *testing.B) {
+	benchmarkCalcZpSolve(b, true)
+}
@@ -0,0 +1,399 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fentec-project/gofe/internal"
+)
+
+// StopInterval is the number of baby steps or giant steps between two
+// calls of the stop function of SolveStop.
+const StopInterval = 1024
+
+// ErrNotFound is wrapped by the errors of the table search when there
+// is no discrete logarithm within the bound.
+var ErrNotFound = errors.New("failed to find the discrete logarithm within bound")
+
+// Table is a precomputed table of baby steps g^j mod p for
+// j in [0, m), used by the baby-step giant-step method. It allows
+// computing discrete logarithms in [-m², m²) without recomputing
+// the baby steps, and can be extended to larger bounds. It is safe
+// for concurrent use.
+type Table struct {
+	mu sync.RWMutex
+	p  *big.Int
+	g  *big.Int
+	m  *big.Int
+	// g^m mod p, the next baby step
+	next *big.Int
+	// g^-m mod p
+	giantStep *big.Int
+	// big.Int cannot be a key, thus we use a stringified
+	// bytes representation of the integer
+	steps map[string]int64
+	// number of lookups in the table, counted in cache
+	stats *tableCounters
+}
+
+// NewTable computes the table of baby steps of generator g in Z_p
+// needed to compute discrete logarithms x with |x| <= bound. It
+// returns an error if bound is not positive or is not smaller than
+// MaxBound.
+func NewTable(p, g, bound *big.Int) (*Table, error) {
+	m, err := tableSteps(bound)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTable(p, g, m), nil
+}
+
+// tableSteps returns the number of baby steps m = floor(sqrt(bound)) + 1
+// of a table for bound, for which m² > bound.
+func tableSteps(bound *big.Int) (*big.Int, error) {
+	if bound.Sign() <= 0 || bound.Cmp(MaxBound) >= 0 {
+		return nil, fmt.Errorf("bound should be in (0, %s)", MaxBound)
+	}
+	m := new(big.Int).Sqrt(bound)
+
+	return m.Add(m, big.NewInt(1)), nil
+}
+
+// newTable computes the table of m baby steps of generator g in Z_p.
+func newTable(p, g, m *big.Int) *Table {
+	t := emptyTable(p, g, m)
+	t.extend(m)
+
+	return t
+}
+
+// emptyTable returns a table of generator g in Z_p without baby steps,
+// with room for m of them.
+func emptyTable(p, g, m *big.Int) *Table {
+	return &Table{
+		p:     p,
+		g:     g,
+		m:     big.NewInt(0),
+		next:  big.NewInt(1),
+		steps: make(map[string]int64, m.Int64()),
+	}
+}
+
+// Extend adds the baby steps needed to compute discrete logarithms x
+// with |x| <= newBound, keeping the ones computed so far, so that a
+// table built for a smaller bound need not be rebuilt. It does nothing
+// if the table already covers newBound. It returns an error if
+// newBound is not positive or is not smaller than MaxBound.
+func (t *Table) Extend(newBound *big.Int) error {
+	m, err := tableSteps(newBound)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.extend(m)
+
+	return nil
+}
+
+// extend adds the baby steps g^j for j in [t.m, m) and updates the
+// giant step, if m > t.m. It is called with t.mu held or before the
+// table is shared.
+func (t *Table) extend(m *big.Int) {
+	_ = t.extendStop(m, nil)
+}
+
+// extendStop works like extend, but if stop is not nil, it calls stop
+// with 0 every StopInterval baby steps and returns its error if it is
+// not nil. The table is then left incomplete and should not be used.
+func (t *Table) extendStop(m *big.Int, stop func(searched *big.Int) error) error {
+	if m.Cmp(t.m) <= 0 {
+		return nil
+	}
+	for j := t.m.Int64(); j < m.Int64(); j++ {
+		if stop != nil && j%StopInterval == 0 {
+			if err := stop(big.NewInt(0)); err != nil {
+				return err
+			}
+		}
+		// the smallest j is kept if g^j repeats
+		if _, ok := t.steps[string(t.next.Bytes())]; !ok {
+			t.steps[string(t.next.Bytes())] = j
+		}
+		t.next.Mod(t.next.Mul(t.next, t.g), t.p)
+	}
+	t.m = new(big.Int).Set(m)
+	t.giantStep = new(big.Int).ModInverse(t.g, t.p)
+	t.giantStep.Exp(t.giantStep, m, t.p)
+
+	return nil
+}
+
+// Bound returns the largest bound m² - 1 covered by the table.
+func (t *Table) Bound() *big.Int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	b := new(big.Int).Mul(t.m, t.m)
+
+	return b.Sub(b, big.NewInt(1))
+}
+
+// Solve returns x with |x| <= bound such that g^x = h (mod P) using
+// the baby steps of the table. It returns an error if g and P differ
+// from the generator and the group of the table, if bound exceeds
+// Bound, or if there is no such x. The order Q is not used.
+func (t *Table) Solve(h, g, P, Q, bound *big.Int) (*big.Int, error) {
+	if g.Cmp(t.g) != 0 || P.Cmp(t.p) != 0 {
+		return nil, fmt.Errorf("table was computed for a different generator or group")
+	}
+	if bound.Cmp(t.Bound()) > 0 {
+		return nil, fmt.Errorf("bound should not exceed %s for the table", t.Bound())
+	}
+
+	return t.search(h, bound, true)
+}
+
+// lookup returns j if y = g^j for a baby step j. It is called with
+// t.mu held for reading.
+func (t *Table) lookup(y *big.Int) (int64, bool) {
+	return t.lookupBytes(y.Bytes())
+}
+
+// search returns x with |x| <= bound such that g^x = h, searching
+// among negative values as well if neg is true. Positive and negative
+// candidates are checked alternately with increasing absolute value
+// of the giant step, thus the search ends as soon as the solution is
+// found, or once the giant steps exceed the bound. It returns an error
+// if neg is true and h is not invertible modulo p, e.g. 0, and an
+// error wrapping ErrNotFound if there is no such x.
+func (t *Table) search(h, bound *big.Int, neg bool) (*big.Int, error) {
+	return t.searchStop(h, bound, neg, nil)
+}
+
+// searchStop works like search, but if stop is not nil, it calls stop
+// every StopInterval giant steps with searched, such that all x with
+// |x| < searched were searched so far, and returns its error if it is
+// not nil.
+func (t *Table) searchStop(h, bound *big.Int, neg bool, stop func(searched *big.Int) error) (*big.Int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	y := new(big.Int).Set(h)
+	var yNeg *big.Int
+	if neg {
+		// g^-x = h if g^x = h^-1
+		yNeg = new(big.Int).ModInverse(h, t.p)
+		if yNeg == nil {
+			return nil, fmt.Errorf("element is not invertible")
+		}
+	}
+
+	res := new(big.Int)
+	start := new(big.Int)
+	for i := int64(0); i < t.m.Int64(); i++ {
+		if start.Mul(big.NewInt(i), t.m).Cmp(bound) > 0 {
+			break
+		}
+		if stop != nil && i%StopInterval == 0 {
+			if err := stop(new(big.Int).Set(start)); err != nil {
+				return nil, err
+			}
+		}
+		if j, ok := t.lookup(y); ok {
+			res.Add(start, big.NewInt(j))
+			if res.Cmp(bound) <= 0 {
+				return res, nil
+			}
+		}
+		if neg {
+			if j, ok := t.lookup(yNeg); ok {
+				res.Add(start, big.NewInt(j))
+				if res.Cmp(bound) <= 0 {
+					return res.Neg(res), nil
+				}
+			}
+			yNeg.Mod(yNeg.Mul(yNeg, t.giantStep), t.p)
+		}
+		y.Mod(y.Mul(y, t.giantStep), t.p)
+	}
+
+	return nil, fmt.Errorf("%w %s", ErrNotFound, bound)
+}
+
+// SolveStop returns x with |x| <= bound such that g^x = h (mod p), or
+// x in [0, bound] if neg is false, with the search of a table of baby
+// steps built for bound and not kept afterwards. If stop is not nil,
+// it is called every StopInterval baby steps with 0 while the table is
+// built, and every StopInterval giant steps with searched, such that
+// all x with |x| < searched were searched so far. The computation ends
+// with the error of stop if it is not nil. It returns an error if
+// bound is not in [0, MaxBound], if neg is true and h is not
+// invertible modulo p, and an error wrapping ErrNotFound if there is
+// no such x. It is not a method of Table, like SolveWorkspace.
+func SolveStop(h, g, p, bound *big.Int, neg bool, stop func(searched *big.Int) error) (*big.Int, error) {
+	if bound.Sign() < 0 || bound.Cmp(MaxBound) > 0 {
+		return nil, fmt.Errorf("bound should be in [0, %s]", MaxBound)
+	}
+	// m² > bound
+	m := new(big.Int).Sqrt(bound)
+	m.Add(m, big.NewInt(1))
+	t := emptyTable(p, g, m)
+	if err := t.extendStop(m, stop); err != nil {
+		return nil, err
+	}
+
+	return t.searchStop(h, bound, neg, stop)
+}
+
+// SolveWorkspace returns x with |x| <= bound such that g^x = h
+// (mod P) and true, or false if there is no such x, like t.Solve, but
+// does not allocate memory once the temporaries of ws have grown. It
+// takes y = h and yNeg = h^-1 mod P, which it uses as temporaries and
+// overwrites, and requires bound to be at most t.Bound. It is not a
+// method of Table, so that the internal workspace does not show in
+// the API of the public alias of Table.
+func SolveWorkspace(t *Table, ws *internal.Workspace, y, yNeg *big.Int, bound int64) (int64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	m := t.m.Int64()
+	for i := int64(0); i < m && i*m <= bound; i++ {
+		if j, ok := t.lookupBytes(ws.Bytes(y)); ok && i*m+j <= bound {
+			return i*m + j, true
+		}
+		if j, ok := t.lookupBytes(ws.Bytes(yNeg)); ok && i*m+j <= bound {
+			return -(i*m + j), true
+		}
+		ws.MulMod(y, y, t.giantStep, t.p)
+		ws.MulMod(yNeg, yNeg, t.giantStep, t.p)
+	}
+
+	return 0, false
+}
+
+// lookupBytes works like lookup, for the bytes of y.
+func (t *Table) lookupBytes(b []byte) (int64, bool) {
+	if t.stats != nil {
+		atomic.AddUint64(&t.stats.lookups, 1)
+	}
+	j, ok := t.steps[string(b)]
+
+	return j, ok
+}
+
+// TableStats holds the counters of a TableCache.
+type TableStats struct {
+	// number of tables built
+	Builds uint64
+	// number of requests for a table served by an already built table
+	Reuses uint64
+	// number of tables extended to a larger bound instead of being
+	// rebuilt
+	Extensions uint64
+	// number of lookups in the tables
+	Lookups uint64
+}
+
+type tableCounters struct {
+	builds     uint64
+	reuses     uint64
+	extensions uint64
+	lookups    uint64
+}
+
+// TableCache holds baby-step tables, so that they are built only once
+// and reused across computations of discrete logarithms with the same
+// group and generator. A table built for a smaller bound is extended
+// when a larger bound is requested. It is safe for concurrent use.
+type TableCache struct {
+	mu     sync.Mutex
+	tables map[string]*Table
+	stats  tableCounters
+}
+
+// NewTableCache returns an empty TableCache.
+func NewTableCache() *TableCache {
+	return &TableCache{
+		tables: make(map[string]*Table),
+	}
+}
+
+// get returns a table of at least m baby steps of g in Z_p, building
+// it if there is no table for p and g in the cache yet, and extending
+// it if it has fewer baby steps.
+func (c *TableCache) get(p, g, m *big.Int) *Table {
+	key := p.String() + "," + g.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.tables[key]; ok {
+		t.mu.Lock()
+		if t.m.Cmp(m) < 0 {
+			t.extend(m)
+			atomic.AddUint64(&c.stats.extensions, 1)
+		} else {
+			atomic.AddUint64(&c.stats.reuses, 1)
+		}
+		t.mu.Unlock()
+		return t
+	}
+	t := newTable(p, g, m)
+	t.stats = &c.stats
+	c.tables[key] = t
+	atomic.AddUint64(&c.stats.builds, 1)
+
+	return t
+}
+
+// Precompute builds the table of baby steps of g in Z_p needed to
+// compute discrete logarithms x with |x| <= bound, or extends the table
+// in the cache to cover bound, so that the first computation with the
+// cache does not pay for building it. It returns an error if bound is
+// not positive or is not smaller than MaxBound.
+func (c *TableCache) Precompute(p, g, bound *big.Int) error {
+	m, err := tableSteps(bound)
+	if err != nil {
+		return err
+	}
+	c.get(p, g, m)
+
+	return nil
+}
+
+// Clear removes all the tables from the cache, releasing their memory
+// once they are not in use anymore. The counters are kept.
+func (c *TableCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tables = make(map[string]*Table)
+}
+
+// Stats returns the current values of the counters of the cache.
+func (c *TableCache) Stats() TableStats {
+	return TableStats{
+		Builds:     atomic.LoadUint64(&c.stats.builds),
+		Reuses:     atomic.LoadUint64(&c.stats.reuses),
+		Extensions: atomic.LoadUint64(&c.stats.extensions),
+		Lookups:    atomic.LoadUint64(&c.stats.lookups),
+	}
+}
@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlog
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/fentec-project/gofe/internal"
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcZp_WithTableCache(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	cache := NewTableCache()
+	assert.Equal(t, TableStats{}, cache.Stats())
+
+	calc, err := NewCalc().InZp(params.p, params.order)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+	calc = calc.WithTableCache(cache).WithNeg().WithBound(big.NewInt(1000))
+
+	xs := []int64{-1000, -999, -1, 0, 1, 31, 32, 500, 1000}
+	for _, x := range xs {
+		h := internal.ModExp(params.g, big.NewInt(x), params.p)
+		res, err := calc.BabyStepGiantStep(h, params.g)
+		if err != nil {
+			t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+		}
+		assert.Equal(t, 0, res.Cmp(big.NewInt(x)), "BabyStepGiantStep result is wrong")
+	}
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should be built once")
+	assert.Equal(t, uint64(len(xs)-1), stats.Reuses, "table should be reused")
+	assert.True(t, stats.Lookups >= uint64(len(xs)), "lookups should be counted")
+
+	// values outside of the bound are not found
+	h := internal.ModExp(params.g, big.NewInt(1001), params.p)
+	_, err = calc.BabyStepGiantStep(h, params.g)
+	assert.Error(t, err)
+
+	// elements that are not invertible, e.g. from a malformed
+	// ciphertext, are rejected
+	for _, h := range []*big.Int{big.NewInt(0), new(big.Int).Set(params.p)} {
+		_, err = calc.BabyStepGiantStep(h, params.g)
+		assert.Error(t, err)
+	}
+}
+
+func TestCalcZp_PrecomputeBabySteps(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	calc, err := NewCalc().InZp(params.p, params.order)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+	calc, err = calc.WithNeg().WithBound(big.NewInt(1000)).PrecomputeBabySteps(params.g)
+	if err != nil {
+		t.Fatalf("Error during precomputation of baby steps: %v", err)
+	}
+	assert.Equal(t, uint64(1), calc.tables.Stats().Builds, "table should be built in advance")
+
+	for _, x := range []int64{-1000, -1, 0, 7, 1000} {
+		h := internal.ModExp(params.g, big.NewInt(x), params.p)
+		res, err := calc.BabyStepGiantStep(h, params.g)
+		if err != nil {
+			t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+		}
+		assert.Equal(t, 0, res.Cmp(big.NewInt(x)), "BabyStepGiantStep result is wrong")
+	}
+
+	stats := calc.tables.Stats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should not be rebuilt")
+	assert.Equal(t, uint64(5), stats.Reuses, "precomputed table should be reused")
+
+	assert.Error(t, NewTableCache().Precompute(params.p, params.g, MaxBound),
+		"bound should be too large for a table")
+}
+
+func TestTableCache_Concurrent(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	cache := NewTableCache()
+	calc, err := NewCalc().InZp(params.p, params.order)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+	calc = calc.WithTableCache(cache).WithNeg().WithBound(big.NewInt(10000))
+
+	n := 16
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			x := big.NewInt(int64(i*500 - 4000))
+			h := internal.ModExp(params.g, x, params.p)
+			res, err := calc.BabyStepGiantStep(h, params.g)
+			if err == nil && res.Cmp(x) != 0 {
+				err = assert.AnError
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Builds)
+	assert.Equal(t, uint64(n-1), stats.Reuses)
+}
+
+func TestTable_Extend(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	table, err := NewTable(params.p, params.g, big.NewInt(1<<10))
+	if err != nil {
+		t.Fatalf("Error during table creation: %v", err)
+	}
+	h := internal.ModExp(params.g, big.NewInt(-(1 << 11)), params.p)
+	_, err = table.Solve(h, params.g, params.p, params.order, big.NewInt(1<<11))
+	assert.Error(t, err, "bound beyond the table should be rejected")
+
+	for _, k := range []int64{11, 16} {
+		bound := big.NewInt(1 << k)
+		if err := table.Extend(bound); err != nil {
+			t.Fatalf("Error during table extension: %v", err)
+		}
+		assert.True(t, table.Bound().Cmp(bound) >= 0)
+
+		// the extended table equals the table built for the bound
+		fresh, err := NewTable(params.p, params.g, bound)
+		if err != nil {
+			t.Fatalf("Error during table creation: %v", err)
+		}
+		assert.Equal(t, fresh.steps, table.steps)
+		assert.Equal(t, 0, fresh.giantStep.Cmp(table.giantStep))
+
+		for _, x := range []int64{-(1 << k), -3, 0, 1 << (k - 1), 1 << k} {
+			h := internal.ModExp(params.g, big.NewInt(x), params.p)
+			res, err := table.Solve(h, params.g, params.p, params.order, bound)
+			if err != nil {
+				t.Fatalf("Error during computing the discrete logarithm: %v", err)
+			}
+			assert.Equal(t, x, res.Int64())
+		}
+	}
+
+	// extending to a smaller bound keeps the table
+	bound := table.Bound()
+	assert.NoError(t, table.Extend(big.NewInt(10)))
+	assert.Equal(t, bound, table.Bound())
+	assert.Error(t, table.Extend(MaxBound))
+}
+
+func TestTableCache_Extend(t *testing.T) {
+	params, err := getParams()
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	cache := NewTableCache()
+	calc, err := NewCalc().InZp(params.p, params.order)
+	if err != nil {
+		t.Fatal("Error in creation of new CalcZp:", err)
+	}
+	calc = calc.WithTableCache(cache).WithNeg()
+
+	for _, bound := range []int64{1000, 4000, 2000} {
+		h := internal.ModExp(params.g, big.NewInt(-bound), params.p)
+		res, err := calc.WithBound(big.NewInt(bound)).BabyStepGiantStep(h, params.g)
+		if err != nil {
+			t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+		}
+		assert.Equal(t, -bound, res.Int64())
+	}
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Builds, "table should be built once")
+	assert.Equal(t, uint64(1), stats.Extensions, "table should be extended for the larger bound")
+	assert.Equal(t, uint64(1), stats.Reuses, "table should be reused for the smaller bound")
+
+	// the larger table does not find values beyond the smaller bound
+	h := internal.ModExp(params.g, big.NewInt(1001), params.p)
+	_, err = calc.WithBound(big.NewInt(1000)).BabyStepGiantStep(h, params.g)
+	assert.Error(t, err)
+}
+
+func TestSolveStop(t *testing.T) {
+	// a group of order larger than the bound
+	key, err := keygen.NewElGamal(64)
+	if err != nil {
+		t.Fatalf("Error during parameters generation: %v", err)
+	}
+
+	bound := big.NewInt(1 << 22)
+	for _, x := range []int64{-(1 << 22), -1, 0, 5, 1 << 22} {
+		h := internal.ModExp(key.G, big.NewInt(x), key.P)
+		res, err := SolveStop(h, key.G, key.P, bound, true, nil)
+		if err != nil {
+			t.Fatalf("Error in baby step - giant step algorithm: %v", err)
+		}
+		assert.Equal(t, x, res.Int64())
+	}
+
+	// without negative values
+	h := internal.ModExp(key.G, big.NewInt(-5), key.P)
+	_, err = SolveStop(h, key.G, key.P, bound, false, nil)
+	assert.True(t, errors.Is(err, ErrNotFound), "expected not found, got %v", err)
+	_, err = SolveStop(big.NewInt(0), key.G, key.P, bound, true, nil)
+	assert.Error(t, err, "element that is not invertible should be rejected")
+	_, err = SolveStop(h, key.G, key.P, new(big.Int).Add(MaxBound, big.NewInt(1)), true, nil)
+	assert.Error(t, err, "bound should be too large")
+
+	// stop is called while building the table and during the search,
+	// with the searched interval growing
+	var searched []int64
+	errStop := errors.New("stop")
+	h = internal.ModExp(key.G, new(big.Int).Add(bound, big.NewInt(1)), key.P)
+	_, err = SolveStop(h, key.G, key.P, bound, true, func(s *big.Int) error {
+		searched = append(searched, s.Int64())
+		if s.Int64() > 0 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	// 2049 baby steps give 3 calls with 0, then giant steps of 2049
+	assert.Equal(t, []int64{0, 0, 0, 0, int64(StopInterval) * 2049}, searched)
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DotMod calculates the inner product of a and b modulo m, with the
+// result in [0, m). It returns an error if a and b differ in length.
+//
+// The products are accumulated in a single integer, reusing one buffer
+// for the terms, and reduced only once at the end. The accumulator
+// never exceeds max|a_i| * max|b_i| * len(a), i.e. it is only about
+// log2(len(a)) bits longer than the largest term, so reducing each term
+// would only add divisions.
+func DotMod(a, b []*big.Int, m *big.Int) (*big.Int, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("vectors should be of same length")
+	}
+
+	acc := new(big.Int)
+	term := new(big.Int)
+	for i, c := range a {
+		acc.Add(acc, term.Mul(c, b[i]))
+	}
+
+	return acc.Mod(acc, m), nil
+}
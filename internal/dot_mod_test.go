@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomDotModInput returns n elements of [0, q) and n coordinates
+// of the maximal magnitude bound with alternating signs, modulo a
+// random prime q of the given bit length.
+func randomDotModInput(t testing.TB, n, bits int, bound *big.Int) ([]*big.Int, []*big.Int, *big.Int) {
+	q, err := rand.Prime(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("Error during prime generation: %v", err)
+	}
+	sampler := sample.NewUniformRange(big.NewInt(0), q)
+	a := make([]*big.Int, n)
+	b := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		if a[i], err = sampler.Sample(); err != nil {
+			t.Fatalf("Error during random generation: %v", err)
+		}
+		b[i] = new(big.Int).Set(bound)
+		if i%3 == 0 {
+			b[i].Neg(b[i])
+		}
+	}
+
+	return a, b, q
+}
+
+// dotModNaive calculates the inner product in full precision and
+// reduces it at the end.
+func dotModNaive(a, b []*big.Int, m *big.Int) *big.Int {
+	prod := new(big.Int)
+	for i := range a {
+		prod.Add(prod, new(big.Int).Mul(a[i], b[i]))
+	}
+
+	return prod.Mod(prod, m)
+}
+
+func TestDotMod(t *testing.T) {
+	a, b, q := randomDotModInput(t, 50000, 1024, new(big.Int).Lsh(big.NewInt(1), 200))
+	res, err := DotMod(a, b, q)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dotModNaive(a, b, q).Cmp(res))
+	assert.True(t, res.Sign() >= 0 && res.Cmp(q) < 0)
+
+	// a negative sum is reduced into [0, q)
+	res, err = DotMod([]*big.Int{big.NewInt(3)}, []*big.Int{big.NewInt(-5)}, big.NewInt(7))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), res.Int64())
+
+	res, err = DotMod(nil, nil, q)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, res.Sign())
+
+	_, err = DotMod(a, b[1:], q)
+	assert.Error(t, err)
+}
+
+func BenchmarkDotMod(b *testing.B) {
+	x, y, q := randomDotModInput(b, 100000, 2048, big.NewInt(1<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DotMod(x, y, q)
+	}
+}
+
+func BenchmarkDotModNaive(b *testing.B) {
+	x, y, q := randomDotModInput(b, 100000, 2048, big.NewInt(1<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dotModNaive(x, y, q)
+	}
+}
@@ -18,6 +18,7 @@ package keygen
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/fentec-project/gofe/sample"
@@ -39,16 +40,86 @@ func NewElGamal(modulusLength int) (*ElGamal, error) {
 		return nil, fmt.Errorf("failed to generate safe prime")
 	}
 
+	return newElGamal(p, sample.NewUniformRange(big.NewInt(3), p))
+}
+
+// NewElGamalFromReader creates parameters for ElGamal scheme, using
+// random as the only source of randomness. The same stream of random
+// bytes always yields the same parameters.
+func NewElGamalFromReader(modulusLength int, random io.Reader) (*ElGamal, error) {
+	p, err := GetSafePrimeFromReader(modulusLength, random)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate safe prime")
+	}
+
+	return newElGamal(p, sample.NewUniformRangeFromReader(big.NewInt(3), p, random))
+}
+
+// NewElGamalFromPrime creates parameters for ElGamal scheme from
+// a given safe prime p. The generator is derived from p with
+// DeriveGenerator, thus the same p always yields the same G. It
+// returns an error if p is not a safe prime, i.e. if p or (p - 1) / 2
+// is not a prime.
+func NewElGamalFromPrime(p *big.Int) (*ElGamal, error) {
+	if p.Cmp(big.NewInt(7)) < 0 || !p.ProbablyPrime(20) {
+		return nil, fmt.Errorf("p is not a prime")
+	}
+	q := new(big.Int).Rsh(p, 1)
+	if !q.ProbablyPrime(20) {
+		return nil, fmt.Errorf("(p - 1) / 2 is not a prime")
+	}
+
+	g, err := DeriveGenerator(p, q)
+	if err != nil {
+		return nil, err
+	}
+	x, err := sample.NewUniformRange(big.NewInt(3), p).Sample()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ElGamal{
+		Y: new(big.Int).Exp(g, x, p),
+		G: g,
+		P: p,
+		Q: q,
+	}, nil
+}
+
+// DeriveGenerator deterministically derives a generator of the
+// subgroup of order q of Z_p*, where q is a prime dividing p - 1. The
+// rule is: find the smallest integer b >= 2 such that b² mod p has
+// order q, and return G = b² mod p. Since q is a prime, b² mod p has
+// order q exactly when it is not 1 and (b²)^q mod p = 1. For a safe
+// prime p = 2q + 1 every square other than 1 has order q, thus the
+// rule always yields b = 2 and G = 4.
+//
+// It returns an error if there is no such b.
+func DeriveGenerator(p, q *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	pMinusOne := new(big.Int).Sub(p, one)
+	for b := big.NewInt(2); b.Cmp(pMinusOne) < 0; b.Add(b, one) {
+		g := new(big.Int).Exp(b, big.NewInt(2), p)
+		if g.Cmp(one) != 0 && new(big.Int).Exp(g, q, p).Cmp(one) == 0 {
+			return g, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no square in Z_%s has order %s", p, q)
+}
+
+// newElGamal derives the remaining ElGamal parameters from a safe
+// prime p, sampling randomness with the provided sampler.
+func newElGamal(p *big.Int, sampler sample.Sampler) (*ElGamal, error) {
+	var err error
 	zero := big.NewInt(0)
 	one := big.NewInt(1)
 	two := big.NewInt(2)
-	three := big.NewInt(3)
 
 	// q = (p - 1) / 2
 	q := new(big.Int).Sub(p, one)
 	q.Div(q, two)
 	var g *big.Int
-	sampler := sample.NewUniformRange(three, p)
 
 	for {
 		g, err = sampler.Sample()
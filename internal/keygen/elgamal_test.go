@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keygen_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveGenerator(t *testing.T) {
+	for _, c := range []struct {
+		p, q, g int64
+	}{{23, 11, 4}, {2039, 1019, 4}, {29, 7, 16}} {
+		p, q := big.NewInt(c.p), big.NewInt(c.q)
+		g, err := keygen.DeriveGenerator(p, q)
+		if err != nil {
+			t.Fatalf("Error during generator derivation: %v", err)
+		}
+		assert.Equal(t, c.g, g.Int64(), "p = %d, q = %d", c.p, c.q)
+		assert.Equal(t, int64(1), new(big.Int).Exp(g, q, p).Int64(), "G should be of order Q")
+	}
+
+	// 7 does not divide 22
+	_, err := keygen.DeriveGenerator(big.NewInt(23), big.NewInt(7))
+	assert.Error(t, err)
+}
+
+func TestNewElGamalFromPrime_DeterministicGenerator(t *testing.T) {
+	p := big.NewInt(2039)
+	key1, err := keygen.NewElGamalFromPrime(p)
+	if err != nil {
+		t.Fatalf("Error during parameter generation: %v", err)
+	}
+	key2, err := keygen.NewElGamalFromPrime(p)
+	if err != nil {
+		t.Fatalf("Error during parameter generation: %v", err)
+	}
+	assert.Equal(t, 0, key1.G.Cmp(key2.G), "the same prime should yield the same generator")
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keygen_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrecompGroup(t *testing.T) {
+	one := big.NewInt(1)
+	lengths := []int{1024, 1536, 2048, 2560, 3072, 4096}
+	assert.Equal(t, lengths, keygen.PrecompModulusLengths())
+
+	for _, n := range lengths {
+		group, err := keygen.NewPrecompGroup(n)
+		if err != nil {
+			t.Fatalf("Error during group creation for %d: %v", n, err)
+		}
+		assert.Equal(t, n, group.P.BitLen(), "wrong modulus length")
+		assert.Equal(t, 0, new(big.Int).Rsh(group.P, 1).Cmp(group.Q), "Q should be (P - 1) / 2")
+		assert.True(t, group.P.ProbablyPrime(1), "P should be a prime")
+		assert.True(t, group.Q.ProbablyPrime(1), "Q should be a prime")
+		for _, g := range []*big.Int{group.G, group.H} {
+			assert.NotEqual(t, 0, g.Cmp(one), "generator should not be 1")
+			assert.Equal(t, 0, new(big.Int).Exp(g, group.Q, group.P).Cmp(one), "generator should be of order Q")
+		}
+
+		// each call returns new values
+		group.P.SetInt64(0)
+		again, _ := keygen.NewPrecompGroup(n)
+		assert.Equal(t, n, again.P.BitLen())
+	}
+
+	for _, n := range []int{0, 512, 1000, 8192} {
+		_, err := keygen.NewPrecompGroup(n)
+		assert.EqualError(t, err, "modulus length should be one of values 1024, 1536, 2048, 2560, 3072, or 4096")
+	}
+}
@@ -26,7 +26,27 @@ import (
 // GetSafePrime returns a safe prime p (p = 2*p1 + 2 where p1 is prime too).
 func GetSafePrime(bits int) (p *big.Int, err error) {
 	p1 := GetGermainPrime(bits - 1)
-	p = big.NewInt(0)
+
+	return safePrimeFromGermain(p1, bits)
+}
+
+// GetSafePrimeFromReader returns a safe prime p (p = 2*p1 + 2 where p1
+// is prime too), using random as the only source of randomness. In
+// contrast to GetSafePrime the search runs in a single goroutine, thus
+// the same stream of random bytes always yields the same prime.
+func GetSafePrimeFromReader(bits int, random io.Reader) (*big.Int, error) {
+	// a buffered channel and a nil quit channel make germainPrime
+	// run to completion without blocking
+	c := make(chan *big.Int, 1)
+	if _, err := germainPrime(bits-1, random, c, nil); err != nil {
+		return nil, err
+	}
+
+	return safePrimeFromGermain(<-c, bits)
+}
+
+func safePrimeFromGermain(p1 *big.Int, bits int) (*big.Int, error) {
+	p := big.NewInt(0)
 	p.Mul(p1, big.NewInt(2))
 	p.Add(p, big.NewInt(1))
 
@@ -46,7 +66,7 @@ func GetGermainPrime(bits int) (p *big.Int) {
 	c := make(chan *big.Int)
 	quit := make(chan int)
 	for j := int(0); j < 8; j++ {
-		go germainPrime(bits, c, quit)
+		go germainPrime(bits, rand.Reader, c, quit)
 	}
 	msg := <-c
 	// for small values for parameter bits (which should be small only for testing) it sometimes
@@ -70,10 +90,8 @@ var smallPrimesProduct = new(big.Int).SetUint64(16294579238595022365)
 // https://github.com/golang/go/blob/master/src/crypto/rand/util.go
 // germainPrime returns a number, p, of the given size, such that p and 2*p+1 are primes
 // with high probability.
-// germainPrime will return error for any error returned by rand.Read or if bits < 2.
-func germainPrime(bits int, c chan *big.Int, quit chan int) (p *big.Int, err error) {
-	random := rand.Reader
-
+// germainPrime will return error for any error returned by random.Read or if bits < 2.
+func germainPrime(bits int, random io.Reader, c chan *big.Int, quit chan int) (p *big.Int, err error) {
 	if bits < 2 {
 		err = fmt.Errorf("crypto/rand: prime size must be at least 2-bit")
 		return
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keygen
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// MinSecurityBits is the minimal recommended security level in bits,
+// see NIST SP 800-57 Part 1.
+const MinSecurityBits = 112
+
+// securityLevels maps the bit length of the modulus P of a finite
+// field group to the security level it provides, following the
+// comparable strengths of NIST SP 800-57 Part 1, Table 2. The
+// entries are sorted by the bit length.
+var securityLevels = []struct {
+	modulusLength int
+	bits          int
+}{
+	{1024, 80},
+	{2048, 112},
+	{3072, 128},
+	{7680, 192},
+	{15360, 256},
+}
+
+// SecurityBits estimates the security level in bits of the discrete
+// logarithm problem in the subgroup of order Q of Z_P*. It is the level
+// of the largest modulus length in the table of NIST SP 800-57 not
+// exceeding the bit length of P (0 for P shorter than 1024 bits),
+// limited by half the bit length of Q, as generic attacks on the
+// subgroup take sqrt(Q) steps.
+func SecurityBits(P, Q *big.Int) int {
+	bits := 0
+	for _, l := range securityLevels {
+		if P.BitLen() >= l.modulusLength {
+			bits = l.bits
+		}
+	}
+	if q := Q.BitLen() / 2; q < bits {
+		bits = q
+	}
+
+	return bits
+}
+
+// ModulusLengthForSecurity returns the bit length of the modulus P of
+// a finite field group providing the security level of the given bits,
+// according to the same table of NIST SP 800-57 as SecurityBits. It
+// returns an error if bits is not one of the levels of the table, or
+// is below MinSecurityBits.
+func ModulusLengthForSecurity(bits int) (int, error) {
+	if bits >= MinSecurityBits {
+		for _, l := range securityLevels {
+			if l.bits == bits {
+				return l.modulusLength, nil
+			}
+		}
+	}
+
+	var levels []int
+	for _, l := range securityLevels {
+		if l.bits >= MinSecurityBits {
+			levels = append(levels, l.bits)
+		}
+	}
+
+	return 0, fmt.Errorf("security level should be one of %v bits", levels)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keygen_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/internal/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityBits(t *testing.T) {
+	one := big.NewInt(1)
+	for _, c := range []struct {
+		modulusLength int
+		bits          int
+	}{{512, 0}, {1023, 0}, {1024, 80}, {1536, 80}, {2048, 112}, {3072, 128}, {4096, 128}, {7680, 192}, {15360, 256}} {
+		p := new(big.Int).Lsh(one, uint(c.modulusLength-1))
+		q := new(big.Int).Rsh(p, 1)
+		assert.Equal(t, c.bits, keygen.SecurityBits(p, q), "modulus of %d bits", c.modulusLength)
+	}
+
+	// a small subgroup limits the level
+	p := new(big.Int).Lsh(one, 3071)
+	assert.Equal(t, 80, keygen.SecurityBits(p, new(big.Int).Lsh(one, 160)))
+}
+
+func TestModulusLengthForSecurity(t *testing.T) {
+	for bits, modulusLength := range map[int]int{112: 2048, 128: 3072, 192: 7680, 256: 15360} {
+		n, err := keygen.ModulusLengthForSecurity(bits)
+		assert.NoError(t, err)
+		assert.Equal(t, modulusLength, n)
+	}
+	for _, bits := range []int{0, 80, 100, 129} {
+		_, err := keygen.ModulusLengthForSecurity(bits)
+		assert.Error(t, err, "level %d should be rejected", bits)
+	}
+}
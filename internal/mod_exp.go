@@ -31,3 +31,327 @@ func ModExp(g, x, m *big.Int) *big.Int {
 
 	return ret
 }
+
+// ModExpProduct calculates the product of bases[i]^exps[i] in Z_m*,
+// even if some of the exponents are negative. In contrast to
+// multiplying the results of ModExp, the powers with negative
+// exponents are first multiplied together and inverted only once
+// at the end, which saves a modular inversion per negative exponent.
+// The powers with exponents of at most 128 bits are computed with
+// MultiExp, sharing the squarings.
+// Note that splitting the computation with the Chinese remainder
+// theorem does not apply here, since the modulus of the schemes is
+// a prime.
+// It panics if bases and exps differ in length.
+func ModExpProduct(bases, exps []*big.Int, m *big.Int) *big.Int {
+	if len(bases) != len(exps) {
+		panic("number of bases and exponents should be the same")
+	}
+
+	// short exponents, e.g. the coordinates of vectors, are processed
+	// together with MultiExp, long ones, e.g. keys, are left to the
+	// faster big.Int.Exp
+	pos := big.NewInt(1)
+	neg := big.NewInt(1)
+	var posBases, posExps, negBases, negExps []*big.Int
+	t := new(big.Int)
+	for i, b := range bases {
+		e := exps[i]
+		if e.BitLen() > multiExpMaxBits {
+			acc := pos
+			if e.Sign() == -1 {
+				acc = neg
+			}
+			t.Exp(b, new(big.Int).Abs(e), m)
+			acc.Mod(acc.Mul(acc, t), m)
+		} else if e.Sign() == -1 {
+			negBases = append(negBases, b)
+			negExps = append(negExps, new(big.Int).Neg(e))
+		} else {
+			posBases = append(posBases, b)
+			posExps = append(posExps, e)
+		}
+	}
+	pos.Mod(pos.Mul(pos, MultiExp(posBases, posExps, m)), m)
+	neg.Mod(neg.Mul(neg, MultiExp(negBases, negExps, m)), m)
+
+	if neg.Cmp(big.NewInt(1)) == 0 {
+		return pos
+	}
+	neg.ModInverse(neg, m)
+
+	return pos.Mod(pos.Mul(pos, neg), m)
+}
+
+// multiExpMaxBits is the bit length of the longest exponents that
+// ModExpProduct passes to MultiExp.
+const multiExpMaxBits = 128
+
+// MultiExp calculates the product of bases[i]^exps[i] in Z_m* for
+// non-negative exponents with interleaved (Straus) multi-exponentiation:
+// the exponents are processed together, window by window from the most
+// significant bits, thus the squarings are shared by all the pairs and
+// each pair costs only the precomputation of its small powers and one
+// multiplication per non-zero window. The size of the windows depends
+// on the bit length of the largest exponent.
+// It panics if bases and exps differ in length or an exponent is
+// negative.
+func MultiExp(bases, exps []*big.Int, m *big.Int) *big.Int {
+	if len(bases) != len(exps) {
+		panic("number of bases and exponents should be the same")
+	}
+	n := 0
+	for _, e := range exps {
+		if e.Sign() < 0 {
+			panic("exponents should be non-negative")
+		}
+		if e.BitLen() > n {
+			n = e.BitLen()
+		}
+	}
+	w := multiExpWindow(n)
+
+	// tables[i][d] = bases[i]^d for digits 0 < d < 2^w
+	tables := make([][]*big.Int, len(bases))
+	for i, b := range bases {
+		if exps[i].Sign() == 0 {
+			continue
+		}
+		t := make([]*big.Int, 1<<w)
+		t[1] = new(big.Int).Mod(b, m)
+		for d := 2; d < len(t); d++ {
+			t[d] = new(big.Int).Mul(t[d-1], t[1])
+			t[d].Mod(t[d], m)
+		}
+		tables[i] = t
+	}
+
+	// round up to a multiple of the window size
+	n = (n + w - 1) / w * w
+	ret := big.NewInt(1)
+	for k := n - w; k >= 0; k -= w {
+		if k < n-w {
+			for j := 0; j < w; j++ {
+				ret.Mul(ret, ret)
+				ret.Mod(ret, m)
+			}
+		}
+		for i, e := range exps {
+			if tables[i] == nil {
+				continue
+			}
+			var d uint
+			for j := w - 1; j >= 0; j-- {
+				d = d<<1 | e.Bit(k+j)
+			}
+			if d != 0 {
+				ret.Mul(ret, tables[i][d])
+				ret.Mod(ret, m)
+			}
+		}
+	}
+
+	return ret.Mod(ret, m)
+}
+
+// multiExpWindow returns the size of the windows of MultiExp for
+// exponents of at most n bits, which balances the precomputation of
+// 2^w - 2 powers per base against the multiplications per window.
+func multiExpWindow(n int) int {
+	// a base costs about 2^w - 2 + n / w * (1 - 2^-w) multiplications
+	switch {
+	case n <= 16:
+		return 1
+	case n <= 48:
+		return 2
+	case n <= 128:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// mulExp2Window is the number of bits of each exponent processed
+// in a single step of MulExp2.
+const mulExp2Window = 3
+
+// MulExp2 calculates b1^e1 * b2^e2 in Z_m*, even if e1 or e2 is
+// negative. It uses simultaneous exponentiation (Shamir's trick),
+// processing both exponents in a single loop of squarings, thus
+// it is faster than computing the powers separately.
+func MulExp2(b1, e1, b2, e2, m *big.Int) *big.Int {
+	if e1.Sign() == -1 {
+		b1 = new(big.Int).ModInverse(b1, m)
+		e1 = new(big.Int).Neg(e1)
+	}
+	if e2.Sign() == -1 {
+		b2 = new(big.Int).ModInverse(b2, m)
+		e2 = new(big.Int).Neg(e2)
+	}
+
+	// table[i][j] = b1^i * b2^j for i, j < 2^mulExp2Window
+	const size = 1 << mulExp2Window
+	var table [size][size]*big.Int
+	table[0][0] = big.NewInt(1)
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			table[i][0] = new(big.Int).Mul(table[i-1][0], b1)
+			table[i][0].Mod(table[i][0], m)
+		}
+		for j := 1; j < size; j++ {
+			table[i][j] = new(big.Int).Mul(table[i][j-1], b2)
+			table[i][j].Mod(table[i][j], m)
+		}
+	}
+
+	n := e1.BitLen()
+	if e2.BitLen() > n {
+		n = e2.BitLen()
+	}
+	// round up to a multiple of the window size
+	n = (n + mulExp2Window - 1) / mulExp2Window * mulExp2Window
+
+	ret := big.NewInt(1)
+	for k := n - mulExp2Window; k >= 0; k -= mulExp2Window {
+		var i, j uint
+		for w := mulExp2Window - 1; w >= 0; w-- {
+			ret.Mul(ret, ret)
+			ret.Mod(ret, m)
+			i = i<<1 | e1.Bit(k+w)
+			j = j<<1 | e2.Bit(k+w)
+		}
+		if i != 0 || j != 0 {
+			ret.Mul(ret, table[i][j])
+			ret.Mod(ret, m)
+		}
+	}
+
+	return ret.Mod(ret, m)
+}
+
+// fixedBaseWindow is the number of bits of an exponent covered
+// by a single precomputed power in a ModExpTable.
+const fixedBaseWindow = 4
+
+// ModExpTable holds the powers base^(d * 2^(4k)) mod m of a fixed base
+// for all digits d < 16, covering exponents up to a given bit length
+// (fixed-base windowing). With it, ModExpPrecomp computes a power of the
+// base with one modular multiplication per 4 bits of the exponent and
+// no squarings. The table is read-only once built, thus it is safe
+// for concurrent use.
+type ModExpTable struct {
+	base *big.Int
+	m    *big.Int
+	rows [][]*big.Int
+}
+
+// NewModExpTable computes the table of powers of base in Z_m* for
+// exponents of at most bits bits. The table holds 15 * ceil(bits / 4)
+// elements, thus it pays off when many powers of the same base are
+// computed.
+func NewModExpTable(base *big.Int, bits int, m *big.Int) *ModExpTable {
+	return &ModExpTable{
+		base: base,
+		m:    m,
+		rows: fixedBaseTable(base, bits, m),
+	}
+}
+
+// Bits returns the largest bit length of exponents covered by the
+// table.
+func (t *ModExpTable) Bits() int {
+	return len(t.rows) * fixedBaseWindow
+}
+
+// ModExpPrecomp calculates base^x in Z_m* using the table of powers of
+// base and m computed by NewModExpTable, even if x < 0. The result is
+// the same as that of ModExp. It falls back to ModExp if x has more
+// bits than the table covers.
+func ModExpPrecomp(t *ModExpTable, x *big.Int) *big.Int {
+	if x.BitLen() > t.Bits() {
+		return ModExp(t.base, x, t.m)
+	}
+	ret := fixedBaseExp(t.rows, new(big.Int).Abs(x), t.m)
+	if x.Sign() == -1 {
+		ret.ModInverse(ret, t.m)
+	}
+
+	return ret
+}
+
+// ModExpSlice calculates base^exps[i] in Z_mod* for all the exponents,
+// even if some of them are negative. The table of powers of base (and
+// of its inverse for negative exponents) is computed once with
+// NewModExpTable and shared across the exponents, thus each exponent
+// costs only one modular multiplication per 4 bits and no squarings.
+// This pays off compared to calling ModExp in a loop when there are
+// many exponents.
+func ModExpSlice(base *big.Int, exps []*big.Int, mod *big.Int) []*big.Int {
+	bits := 0
+	for _, e := range exps {
+		if e.BitLen() > bits {
+			bits = e.BitLen()
+		}
+	}
+
+	var pos, neg *ModExpTable
+	abs := new(big.Int)
+	res := make([]*big.Int, len(exps))
+	for i, e := range exps {
+		table := &pos
+		b := base
+		if e.Sign() == -1 {
+			table = &neg
+			if neg == nil {
+				b = new(big.Int).ModInverse(base, mod)
+			}
+		}
+		if *table == nil {
+			*table = NewModExpTable(b, bits, mod)
+		}
+		res[i] = ModExpPrecomp(*table, abs.Abs(e))
+	}
+
+	return res
+}
+
+// fixedBaseTable returns the table t of powers of base, where
+// t[k][d] = base^(d * 2^(w * k)) mod m for digits 0 < d < 2^w, with
+// enough rows for exponents of the given bit length.
+func fixedBaseTable(base *big.Int, bits int, m *big.Int) [][]*big.Int {
+	const size = 1 << fixedBaseWindow
+	table := make([][]*big.Int, (bits+fixedBaseWindow-1)/fixedBaseWindow)
+	b := new(big.Int).Mod(base, m)
+	for k := range table {
+		row := make([]*big.Int, size)
+		row[1] = b
+		for d := 2; d < size; d++ {
+			row[d] = new(big.Int).Mul(row[d-1], b)
+			row[d].Mod(row[d], m)
+		}
+		table[k] = row
+		// base^(2^(w * (k+1)))
+		b = new(big.Int).Mul(row[size-1], b)
+		b.Mod(b, m)
+	}
+
+	return table
+}
+
+// fixedBaseExp calculates base^e mod m for a non-negative e using the
+// table of powers of base computed by fixedBaseTable.
+func fixedBaseExp(table [][]*big.Int, e, m *big.Int) *big.Int {
+	ret := big.NewInt(1)
+	for k, row := range table {
+		var d uint
+		for j := fixedBaseWindow - 1; j >= 0; j-- {
+			d = d<<1 | e.Bit(k*fixedBaseWindow+j)
+		}
+		if d != 0 {
+			ret.Mul(ret, row[d])
+			ret.Mod(ret, m)
+		}
+	}
+
+	return ret.Mod(ret, m)
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+// modExpProductNaive multiplies the results of ModExp.
+func modExpProductNaive(bases, exps []*big.Int, m *big.Int) *big.Int {
+	res := big.NewInt(1)
+	for i, b := range bases {
+		res.Mod(res.Mul(res, ModExp(b, exps[i], m)), m)
+	}
+
+	return res
+}
+
+func randomModExpInput(t testing.TB, n, bits int, expBound *big.Int) ([]*big.Int, []*big.Int, *big.Int) {
+	m, err := rand.Prime(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("Error during prime generation: %v", err)
+	}
+	baseSampler := sample.NewUniformRange(big.NewInt(2), m)
+	expSampler := sample.NewUniformRange(new(big.Int).Neg(expBound), expBound)
+
+	bases := make([]*big.Int, n)
+	exps := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		if bases[i], err = baseSampler.Sample(); err != nil {
+			t.Fatalf("Error during random generation: %v", err)
+		}
+		if exps[i], err = expSampler.Sample(); err != nil {
+			t.Fatalf("Error during random generation: %v", err)
+		}
+	}
+
+	return bases, exps, m
+}
+
+func TestModExpProduct(t *testing.T) {
+	bases, exps, m := randomModExpInput(t, 20, 256, big.NewInt(1<<20))
+	assert.Equal(t, 0, modExpProductNaive(bases, exps, m).Cmp(ModExpProduct(bases, exps, m)))
+
+	// only non-negative exponents
+	for i := range exps {
+		exps[i].Abs(exps[i])
+	}
+	assert.Equal(t, 0, modExpProductNaive(bases, exps, m).Cmp(ModExpProduct(bases, exps, m)))
+
+	assert.Equal(t, 0, ModExpProduct(nil, nil, m).Cmp(big.NewInt(1)))
+
+	// long exponents, like the key in DDH decryption, mixed with
+	// short ones
+	bases, exps, m = randomModExpInput(t, 20, 256, big.NewInt(1<<20))
+	exps[0] = new(big.Int).Lsh(big.NewInt(-3), 250)
+	exps[1] = new(big.Int).Lsh(big.NewInt(5), 200)
+	assert.Equal(t, 0, modExpProductNaive(bases, exps, m).Cmp(ModExpProduct(bases, exps, m)))
+}
+
+func BenchmarkModExpProduct(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 100, 4096, big.NewInt(1<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ModExpProduct(bases, exps, m)
+	}
+}
+
+func BenchmarkModExpProductNaive(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 100, 4096, big.NewInt(1<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		modExpProductNaive(bases, exps, m)
+	}
+}
+
+func TestMulExp2(t *testing.T) {
+	for _, bound := range []*big.Int{big.NewInt(1 << 20), new(big.Int).Lsh(big.NewInt(1), 300)} {
+		for i := 0; i < 10; i++ {
+			bases, exps, m := randomModExpInput(t, 2, 256, bound)
+			assert.Equal(t, 0, modExpProductNaive(bases, exps, m).Cmp(
+				MulExp2(bases[0], exps[0], bases[1], exps[1], m)))
+		}
+	}
+
+	m := big.NewInt(101)
+	assert.Equal(t, 0, MulExp2(big.NewInt(3), big.NewInt(0), big.NewInt(5), big.NewInt(0), m).Cmp(big.NewInt(1)))
+	assert.Equal(t, 0, MulExp2(big.NewInt(3), big.NewInt(1), big.NewInt(5), big.NewInt(0), m).Cmp(big.NewInt(3)))
+	assert.Equal(t, 0, MulExp2(big.NewInt(3), big.NewInt(0), big.NewInt(5), big.NewInt(-1), m).Cmp(
+		new(big.Int).ModInverse(big.NewInt(5), m)))
+}
+
+func BenchmarkMulExp2(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 2, 3072, new(big.Int).Lsh(big.NewInt(1), 3071))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MulExp2(bases[0], exps[0], bases[1], exps[1], m)
+	}
+}
+
+func BenchmarkMulExp2Naive(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 2, 3072, new(big.Int).Lsh(big.NewInt(1), 3071))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		modExpProductNaive(bases, exps, m)
+	}
+}
+
+func TestModExpSlice(t *testing.T) {
+	for _, bound := range []*big.Int{big.NewInt(1 << 10), new(big.Int).Lsh(big.NewInt(1), 300)} {
+		bases, exps, m := randomModExpInput(t, 50, 256, bound)
+		exps = append(exps, big.NewInt(0), big.NewInt(1), big.NewInt(-1), new(big.Int).Neg(bound), bound)
+		res := ModExpSlice(bases[0], exps, m)
+		assert.Equal(t, len(exps), len(res))
+		for i, e := range exps {
+			assert.Equal(t, 0, ModExp(bases[0], e, m).Cmp(res[i]), "wrong result for exponent %s", e)
+		}
+	}
+
+	// only non-negative exponents, the base is not reduced
+	m := big.NewInt(101)
+	res := ModExpSlice(big.NewInt(205), []*big.Int{big.NewInt(0), big.NewInt(3), big.NewInt(100)}, m)
+	for i, e := range []int64{0, 3, 100} {
+		assert.Equal(t, 0, ModExp(big.NewInt(205), big.NewInt(e), m).Cmp(res[i]))
+	}
+
+	assert.Equal(t, 0, len(ModExpSlice(big.NewInt(3), nil, m)))
+}
+
+func TestModExpPrecomp(t *testing.T) {
+	for _, bits := range []int{8, 64, 255, 1024} {
+		m, err := rand.Prime(rand.Reader, bits)
+		if err != nil {
+			t.Fatalf("Error during prime generation: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			base, err := rand.Int(rand.Reader, m)
+			if err != nil {
+				t.Fatalf("Error during random generation: %v", err)
+			}
+			if base.Sign() == 0 {
+				base.SetInt64(1)
+			}
+			// tables covering exponents shorter than, as long as and
+			// longer than the modulus
+			table := NewModExpTable(base, (i%3+1)*bits/2, m)
+			for j := 0; j < 20; j++ {
+				expBits := j * bits / 8
+				e, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(expBits)))
+				if err != nil {
+					t.Fatalf("Error during random generation: %v", err)
+				}
+				if j%2 == 1 {
+					e.Neg(e)
+				}
+				assert.Equal(t, 0, ModExp(base, e, m).Cmp(ModExpPrecomp(table, e)),
+					"wrong result for base %s, exponent %s, modulus %s", base, e, m)
+			}
+		}
+	}
+
+	// the base is not reduced
+	m := big.NewInt(101)
+	table := NewModExpTable(big.NewInt(205), 8, m)
+	assert.Equal(t, 8, table.Bits())
+	for _, e := range []int64{-100, -1, 0, 1, 3, 100, 255, 256, 1 << 20} {
+		assert.Equal(t, 0, ModExp(big.NewInt(205), big.NewInt(e), m).Cmp(ModExpPrecomp(table, big.NewInt(e))),
+			"wrong result for exponent %d", e)
+	}
+}
+
+func benchmarkModExpInput(b *testing.B) (*big.Int, *big.Int, *big.Int) {
+	bases, _, m := randomModExpInput(b, 1, 2048, big.NewInt(1))
+	e, err := rand.Int(rand.Reader, m)
+	if err != nil {
+		b.Fatalf("Error during random generation: %v", err)
+	}
+
+	return bases[0], e, m
+}
+
+func BenchmarkModExp(b *testing.B) {
+	base, e, m := benchmarkModExpInput(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ModExp(base, e, m)
+	}
+}
+
+func BenchmarkModExpPrecomp(b *testing.B) {
+	base, e, m := benchmarkModExpInput(b)
+	table := NewModExpTable(base, m.BitLen(), m)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ModExpPrecomp(table, e)
+	}
+}
+
+func BenchmarkModExpSlice(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 100, 2048, big.NewInt(1<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ModExpSlice(bases[0], exps, m)
+	}
+}
+
+func BenchmarkModExpSliceNaive(b *testing.B) {
+	bases, exps, m := randomModExpInput(b, 100, 2048, big.NewInt(1<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range exps {
+			ModExp(bases[0], e, m)
+		}
+	}
+}
+
+func TestMultiExp(t *testing.T) {
+	for _, bound := range []*big.Int{big.NewInt(2), big.NewInt(1 << 8), big.NewInt(1 << 20), new(big.Int).Lsh(big.NewInt(1), 300)} {
+		bases, exps, m := randomModExpInput(t, 20, 256, bound)
+		for i := range exps {
+			exps[i].Abs(exps[i])
+		}
+		exps[3].SetInt64(0)
+		assert.Equal(t, 0, modExpProductNaive(bases, exps, m).Cmp(MultiExp(bases, exps, m)), "bound %s", bound)
+	}
+
+	m := big.NewInt(101)
+	assert.Equal(t, 0, MultiExp(nil, nil, m).Cmp(big.NewInt(1)))
+	assert.Equal(t, 0, MultiExp([]*big.Int{big.NewInt(3)}, []*big.Int{big.NewInt(0)}, m).Cmp(big.NewInt(1)))
+	assert.Panics(t, func() { MultiExp([]*big.Int{big.NewInt(3)}, []*big.Int{big.NewInt(-1)}, m) })
+}
+
+func benchmarkMultiExpInput(b *testing.B) ([]*big.Int, []*big.Int, *big.Int) {
+	// the numerator of DDH decryption with l = 500 and y bounded by 1000
+	bases, exps, m := randomModExpInput(b, 500, 2048, big.NewInt(1000))
+	for i := range exps {
+		exps[i].Abs(exps[i])
+	}
+
+	return bases, exps, m
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	bases, exps, m := benchmarkMultiExpInput(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MultiExp(bases, exps, m)
+	}
+}
+
+func BenchmarkMultiExpNaive(b *testing.B) {
+	bases, exps, m := benchmarkMultiExpInput(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		modExpProductNaive(bases, exps, m)
+	}
+}
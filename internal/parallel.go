@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelRanges splits the indices [0, n) into contiguous ranges, one
+// per worker, and calls f(lo, hi) for each range [lo, hi) in its own
+// goroutine. The number of workers is runtime.GOMAXPROCS(0), which
+// defaults to the number of CPUs, limited by n. Per-worker state, e.g.
+// a sampler, can be created at the start of f. It waits for all the
+// calls and returns the error of the lowest range for which f failed,
+// or nil.
+func ParallelRanges(n int, f func(lo, hi int) error) error {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n <= 0 {
+			return nil
+		}
+		return f(0, n)
+	}
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo, hi := w*n/workers, (w+1)*n/workers
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			defer wg.Done()
+			errs[w] = f(lo, hi)
+		}(w, lo, hi)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelRanges(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, n := range []int{0, 1, 3, 1000} {
+		visits := make([]int32, n)
+		err := ParallelRanges(n, func(lo, hi int) error {
+			for i := lo; i < hi; i++ {
+				atomic.AddInt32(&visits[i], 1)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		for i, v := range visits {
+			assert.Equal(t, int32(1), v, "index %d of %d", i, n)
+		}
+	}
+
+	// the error of the lowest failing range is returned
+	err := ParallelRanges(100, func(lo, hi int) error {
+		if lo > 0 {
+			return fmt.Errorf("range %d", lo)
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "range 25")
+}
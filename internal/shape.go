@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// CheckCiphertextShape checks that cipher has n components and that
+// each of them is an integer in [1, p). It does no exponentiation,
+// thus it is a cheap precondition for rejecting malformed ciphertexts
+// before decryption, but it does not check membership in the subgroup.
+// It returns an error wrapping ErrMalformedCipher, with the offending
+// component, if a check fails.
+func CheckCiphertextShape(cipher data.Vector, n int, p *big.Int) error {
+	if err := CheckCiphertextLength(cipher, n); err != nil {
+		return err
+	}
+	for i, c := range cipher {
+		if c == nil || c.Sign() <= 0 || c.Cmp(p) >= 0 {
+			return fmt.Errorf("%w: component %d is not in [1, p)", ErrMalformedCipher, i)
+		}
+	}
+
+	return nil
+}
+
+// CheckEncryptLengths checks that the master public key and the input
+// vector x of encryption are both of the length l of a scheme. It
+// returns an error wrapping ErrMalformedPubKey or ErrMalformedInput,
+// with the actual and the expected length, if a check fails.
+func CheckEncryptLengths(x, masterPubKey data.Vector, l int) error {
+	if err := CheckPubKeyLength(masterPubKey, l); err != nil {
+		return err
+	}
+
+	return CheckInputLength(x, l)
+}
+
+// CheckPubKeyLength checks that the master public key is of the
+// length l of a scheme. It returns an error wrapping
+// ErrMalformedPubKey, with the actual and the expected length, if it
+// is not.
+func CheckPubKeyLength(masterPubKey data.Vector, l int) error {
+	if len(masterPubKey) != l {
+		return fmt.Errorf("%w: master public key length %d does not match scheme length %d",
+			ErrMalformedPubKey, len(masterPubKey), l)
+	}
+
+	return nil
+}
+
+// CheckCiphertextLength checks that cipher has n components. It
+// returns an error wrapping ErrMalformedCipher, with the actual and the
+// expected number of components, if it does not.
+func CheckCiphertextLength(cipher data.Vector, n int) error {
+	if len(cipher) != n {
+		return fmt.Errorf("%w: got %d components, expected %d",
+			ErrMalformedCipher, len(cipher), n)
+	}
+
+	return nil
+}
+
+// CheckInputLength checks that the vector x is of the length l of a
+// scheme. It returns an error wrapping ErrMalformedInput, with the
+// actual and the expected length, if it is not.
+func CheckInputLength(x data.Vector, l int) error {
+	if len(x) != l {
+		return fmt.Errorf("%w: input vector length %d does not match scheme length %d",
+			ErrMalformedInput, len(x), l)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCiphertextShape(t *testing.T) {
+	p := big.NewInt(23)
+	valid := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(5), big.NewInt(22)})
+	assert.NoError(t, CheckCiphertextShape(valid, 3, p))
+
+	for name, cipher := range map[string]data.Vector{
+		"short": valid[:2],
+		"long":  append(valid.Copy(), big.NewInt(2)),
+		"nil":   {big.NewInt(1), nil, big.NewInt(2)},
+		"zero":  {big.NewInt(1), big.NewInt(0), big.NewInt(2)},
+		"neg":   {big.NewInt(1), big.NewInt(-3), big.NewInt(2)},
+		"p":     {big.NewInt(1), big.NewInt(23), big.NewInt(2)},
+	} {
+		err := CheckCiphertextShape(cipher, 3, p)
+		assert.True(t, errors.Is(err, ErrMalformedCipher), "%s: expected malformed ciphertext, got %v", name, err)
+	}
+}
+
+func TestCheckEncryptLengths(t *testing.T) {
+	v := data.NewConstantVector(3, big.NewInt(1))
+	assert.NoError(t, CheckEncryptLengths(v, v, 3))
+
+	err := CheckEncryptLengths(v, v[:2], 3)
+	assert.True(t, errors.Is(err, ErrMalformedPubKey), "expected malformed public key, got %v", err)
+	assert.Contains(t, err.Error(), "master public key length 2 does not match scheme length 3")
+
+	err = CheckEncryptLengths(nil, v, 3)
+	assert.True(t, errors.Is(err, ErrMalformedInput), "expected malformed input, got %v", err)
+	assert.Contains(t, err.Error(), "input vector length 0 does not match scheme length 3")
+}
+
+func TestCheckCiphertextLength(t *testing.T) {
+	v := data.NewConstantVector(4, big.NewInt(1))
+	assert.NoError(t, CheckCiphertextLength(v, 4))
+
+	for _, c := range []data.Vector{nil, v[:3], append(v, v[0])} {
+		err := CheckCiphertextLength(c, 4)
+		assert.True(t, errors.Is(err, ErrMalformedCipher), "expected malformed ciphertext, got %v", err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("got %d components, expected 4", len(c)))
+	}
+}
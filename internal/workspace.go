@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import "math/big"
+
+// Workspace holds the temporaries of modular arithmetic on
+// non-negative integers that does not allocate memory once the
+// temporaries have grown to the size of the operands, in contrast to
+// the methods of big.Int such as Mod, Exp or ModInverse, which
+// allocate on every call. It is meant for hot paths that are run
+// repeatedly with operands of the same size. The zero value is ready
+// to use. A Workspace is not safe for concurrent use.
+type Workspace struct {
+	prod, quo big.Int
+	a, b      big.Int
+	s0, s1    big.Int
+	buf       []byte
+}
+
+// MulMod sets z to x * y mod m and returns z, for x, y >= 0.
+func (ws *Workspace) MulMod(z, x, y, m *big.Int) *big.Int {
+	ws.prod.Mul(x, y)
+	ws.quo.QuoRem(&ws.prod, m, z)
+
+	return z
+}
+
+// ModInverse sets z to the inverse of x >= 0 modulo an odd m > 1 and
+// returns true, or returns false, leaving z unchanged, if x is not
+// invertible modulo m. It uses the binary extended Euclidean
+// algorithm, which needs only shifts, additions and subtractions, as
+// divisions by small integers allocate.
+func (ws *Workspace) ModInverse(z, x, m *big.Int) bool {
+	u, v := &ws.a, &ws.b
+	// invariant: s0 * x = u and s1 * x = v (mod m), 0 <= s0, s1 < m
+	s0, s1 := &ws.s0, &ws.s1
+	ws.quo.QuoRem(x, m, u)
+	if u.Sign() == 0 {
+		return false
+	}
+	v.Set(m)
+	s0.SetInt64(1)
+	s1.SetInt64(0)
+	for !isOne(u) && !isOne(v) {
+		ws.halve(u, s0, m)
+		ws.halve(v, s1, m)
+		if u.Cmp(v) >= 0 {
+			ws.reduce(u, v, s0, s1, m)
+		} else {
+			ws.reduce(v, u, s1, s0, m)
+		}
+		if u.Sign() == 0 || v.Sign() == 0 {
+			// gcd(x, m) > 1
+			return false
+		}
+	}
+	if isOne(u) {
+		z.Set(s0)
+	} else {
+		z.Set(s1)
+	}
+
+	return true
+}
+
+// halve divides u by 2 while it is even, keeping s * x = u (mod m).
+func (ws *Workspace) halve(u, s, m *big.Int) {
+	for u.Bit(0) == 0 {
+		u.Rsh(u, 1)
+		if s.Bit(0) == 1 {
+			s.Add(s, m)
+		}
+		s.Rsh(s, 1)
+	}
+}
+
+// reduce sets u to u - v and s to s - t mod m.
+func (ws *Workspace) reduce(u, v, s, t, m *big.Int) {
+	u.Sub(u, v)
+	s.Sub(s, t)
+	if s.Sign() < 0 {
+		s.Add(s, m)
+	}
+}
+
+// isOne reports whether x = 1.
+func isOne(x *big.Int) bool {
+	return x.IsInt64() && x.Int64() == 1
+}
+
+// Bytes returns the absolute value of x as a big-endian byte slice,
+// the same as x.Bytes(). The slice is owned by the workspace and is
+// only valid until the next call of Bytes.
+func (ws *Workspace) Bytes(x *big.Int) []byte {
+	n := (x.BitLen() + 7) / 8
+	if cap(ws.buf) < n {
+		ws.buf = make([]byte, n)
+	}
+
+	return x.FillBytes(ws.buf[:n])
+}
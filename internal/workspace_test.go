@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspace(t *testing.T) {
+	var ws Workspace
+	for _, bits := range []int{64, 1024} {
+		m, err := rand.Prime(rand.Reader, bits)
+		if err != nil {
+			t.Fatalf("Error during prime generation: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			x, _ := rand.Int(rand.Reader, m)
+			y, _ := rand.Int(rand.Reader, m)
+
+			z := new(big.Int).Set(x)
+			ws.MulMod(z, z, y, m)
+			expected := new(big.Int).Mul(x, y)
+			assert.Equal(t, 0, z.Cmp(expected.Mod(expected, m)), "MulMod")
+
+			assert.Equal(t, x.Bytes(), ws.Bytes(x), "Bytes")
+
+			inv := new(big.Int)
+			if x.Sign() == 0 {
+				assert.False(t, ws.ModInverse(inv, x, m))
+				continue
+			}
+			assert.True(t, ws.ModInverse(inv, x, m))
+			assert.Equal(t, 0, inv.Cmp(new(big.Int).ModInverse(x, m)), "ModInverse")
+		}
+	}
+
+	// small and non-coprime operands
+	inv := new(big.Int)
+	assert.True(t, ws.ModInverse(inv, big.NewInt(1), big.NewInt(9)))
+	assert.Equal(t, int64(1), inv.Int64())
+	assert.True(t, ws.ModInverse(inv, big.NewInt(20), big.NewInt(9)))
+	assert.Equal(t, int64(5), inv.Int64())
+	assert.False(t, ws.ModInverse(inv, big.NewInt(6), big.NewInt(9)))
+	assert.False(t, ws.ModInverse(inv, big.NewInt(18), big.NewInt(9)))
+	assert.Equal(t, int64(5), inv.Int64(), "z should be unchanged")
+}
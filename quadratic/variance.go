@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quadratic
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+// The variance of values x_1, ..., x_n is E[x^2] - E[x]^2, thus it can
+// be computed from the sum of the values, which is an inner product of
+// x with the all-ones vector (obtainable from any of the inner product
+// schemes, e.g. simple.DDH with a key for data.NewConstantVector(n, 1)),
+// and the sum of squares of the values, which is a quadratic function
+// x^T * I * x of x. The functions below compute the latter with the
+// Quad scheme and combine both into the variance.
+
+// EncryptSquares encrypts input vector x with the given public key in
+// a way that allows decryption of quadratic functions x^T * F * x,
+// i.e. it encrypts x as both vectors x and y. It requires the lengths
+// of vectors n and m of the scheme to be equal. If the ciphertext could
+// not be generated, it returns an error.
+func (q *Quad) EncryptSquares(x data.Vector, pubKey *QuadPubKey) (*QuadCipher, error) {
+	if q.Params.N != q.Params.M {
+		return nil, fmt.Errorf("lengths of vectors x and y of the scheme should be equal")
+	}
+
+	return q.Encrypt(x, x.Copy(), pubKey)
+}
+
+// DeriveSumSquaresKey derives the functional encryption key for the
+// sum of squares of the coordinates of x, to be used with ciphertexts
+// produced by EncryptSquares. It returns an error if the key could not
+// be derived.
+func (q *Quad) DeriveSumSquaresKey(secKey *QuadSecKey) (data.VectorG2, error) {
+	return q.DeriveKey(secKey, identityMatrix(q.Params.N))
+}
+
+// DecryptSumSquares decrypts the ciphertext c produced by EncryptSquares
+// with the key derived by DeriveSumSquaresKey, obtaining the sum of
+// squares of the coordinates of x.
+func (q *Quad) DecryptSumSquares(c *QuadCipher, feKey data.VectorG2) (*big.Int, error) {
+	return q.Decrypt(c, feKey, identityMatrix(q.Params.N))
+}
+
+// Variance combines the sum and the sum of squares of n values into
+// their (population) variance (n * sumSquares - sum^2) / n^2. It
+// returns an error if n is not positive.
+func Variance(sum, sumSquares *big.Int, n int) (*big.Rat, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("number of values should be positive")
+	}
+
+	nBig := big.NewInt(int64(n))
+	num := new(big.Int).Mul(nBig, sumSquares)
+	num.Sub(num, new(big.Int).Mul(sum, sum))
+
+	return new(big.Rat).SetFrac(num, nBig.Mul(nBig, nBig)), nil
+}
+
+// identityMatrix returns the n x n identity matrix.
+func identityMatrix(n int) data.Matrix {
+	id := data.NewConstantMatrix(n, n, big.NewInt(0))
+	for i := 0; i < n; i++ {
+		id[i][i].SetInt64(1)
+	}
+
+	return id
+}
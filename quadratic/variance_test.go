@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quadratic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+	"github.com/fentec-project/gofe/quadratic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariance(t *testing.T) {
+	n := 4
+	bound := big.NewInt(100)
+	x := data.NewVector([]*big.Int{big.NewInt(2), big.NewInt(4), big.NewInt(4), big.NewInt(-5)})
+
+	// the sum is obtained with an inner product scheme
+	ddh, err := simple.NewDDHPrecomp(n, 1024, bound)
+	if err != nil {
+		t.Fatalf("error when creating scheme: %v", err)
+	}
+	masterSecKey, masterPubKey, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("error when generating keys: %v", err)
+	}
+	ones := data.NewConstantVector(n, big.NewInt(1))
+	sumKey, err := ddh.DeriveKey(masterSecKey, ones)
+	if err != nil {
+		t.Fatalf("error when deriving key: %v", err)
+	}
+	cipher, err := ddh.Encrypt(x, masterPubKey)
+	if err != nil {
+		t.Fatalf("error when encrypting: %v", err)
+	}
+	sum, err := ddh.Decrypt(cipher, sumKey, ones)
+	if err != nil {
+		t.Fatalf("error when decrypting: %v", err)
+	}
+
+	// the sum of squares is obtained with the quadratic scheme
+	q, err := quadratic.NewQuad(n, n, bound)
+	if err != nil {
+		t.Fatalf("error when creating scheme: %v", err)
+	}
+	pubKey, secKey, err := q.GenerateKeys()
+	if err != nil {
+		t.Fatalf("error when generating keys: %v", err)
+	}
+	c, err := q.EncryptSquares(x, pubKey)
+	if err != nil {
+		t.Fatalf("error when encrypting: %v", err)
+	}
+	feKey, err := q.DeriveSumSquaresKey(secKey)
+	if err != nil {
+		t.Fatalf("error when deriving key: %v", err)
+	}
+	sumSquares, err := q.DecryptSumSquares(c, feKey)
+	if err != nil {
+		t.Fatalf("error when decrypting: %v", err)
+	}
+	assert.Equal(t, 0, sumSquares.Cmp(big.NewInt(61)), "obtained incorrect sum of squares")
+
+	// mean is 5/4, variance is 61/4 - 25/16 = 219/16
+	v, err := quadratic.Variance(sum, sumSquares, n)
+	if err != nil {
+		t.Fatalf("error when computing variance: %v", err)
+	}
+	assert.Equal(t, 0, v.Cmp(big.NewRat(219, 16)), "obtained incorrect variance")
+
+	_, err = quadratic.Variance(sum, sumSquares, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sample
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// MaxDiscreteLaplaceScale is the largest scale of the DiscreteLaplace
+// sampler. Samples are computed as floating point numbers of magnitude
+// up to about 37 * scale, which are exact integers only below 2^53.
+const MaxDiscreteLaplaceScale = 1 << 47
+
+// DiscreteLaplace samples random values from the discrete Laplace
+// (two-sided geometric) distribution over the integers, where the
+// probability of sampling k is proportional to exp(-|k| / scale).
+// Such noise is used to achieve differential privacy of integer
+// valued results: noise with scale sensitivity / epsilon makes a
+// result with the given sensitivity epsilon-differentially private.
+//
+// The sampler relies on floating point arithmetic, thus the
+// distribution is only approximated to double precision.
+type DiscreteLaplace struct {
+	scale float64
+	// log(exp(-1 / scale)) = -1 / scale
+	logQ float64
+}
+
+// NewDiscreteLaplace returns an instance of the DiscreteLaplace
+// sampler. It accepts the scale of the distribution, which must be
+// positive and not greater than MaxDiscreteLaplaceScale, otherwise
+// Sample returns an error.
+func NewDiscreteLaplace(scale float64) *DiscreteLaplace {
+	return &DiscreteLaplace{
+		scale: scale,
+		logQ:  -1 / scale,
+	}
+}
+
+// Sample samples random values from the discrete Laplace distribution.
+// The value is obtained as the difference of two independent geometric
+// random values.
+func (s *DiscreteLaplace) Sample() (*big.Int, error) {
+	if !(s.scale > 0 && s.scale <= MaxDiscreteLaplaceScale) {
+		return nil, fmt.Errorf("scale should be positive and at most %d, got %v",
+			int64(MaxDiscreteLaplaceScale), s.scale)
+	}
+
+	g1, err := s.geometric()
+	if err != nil {
+		return nil, err
+	}
+	g2, err := s.geometric()
+	if err != nil {
+		return nil, err
+	}
+
+	return big.NewInt(g1 - g2), nil
+}
+
+// geometric samples k >= 0 with probability (1 - q) * q^k,
+// where q = exp(-1 / scale).
+func (s *DiscreteLaplace) geometric() (int64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	// uniform value from (0, 1]
+	u := float64(binary.BigEndian.Uint64(buf[:])>>11+1) / (1 << 53)
+
+	return int64(math.Floor(math.Log(u) / s.logQ)), nil
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2018 XLAB d.o.o
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sample_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fentec-project/gofe/sample"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscreteLaplace(t *testing.T) {
+	// with scale 10 the variance is 2q / (1 - q)^2 ≈ 199.8,
+	// where q = exp(-1/10)
+	testNormalSampler(
+		t,
+		sample.NewDiscreteLaplace(10),
+		paramBounds{
+			meanLow:  -0.2,
+			meanHigh: 0.2,
+			varLow:   190,
+			varHigh:  210,
+		},
+	)
+}
+
+func TestDiscreteLaplace_InvalidScale(t *testing.T) {
+	for _, scale := range []float64{0, -1, math.NaN(), math.Inf(1), 2 * sample.MaxDiscreteLaplaceScale} {
+		_, err := sample.NewDiscreteLaplace(scale).Sample()
+		assert.Error(t, err, "scale %v should be rejected", scale)
+	}
+
+	_, err := sample.NewDiscreteLaplace(sample.MaxDiscreteLaplaceScale).Sample()
+	assert.NoError(t, err)
+}
@@ -18,6 +18,7 @@ package sample
 
 import (
 	"crypto/rand"
+	"io"
 	"math/big"
 )
 
@@ -25,6 +26,8 @@ import (
 type UniformRange struct {
 	min *big.Int
 	max *big.Int
+	// source of randomness, crypto/rand.Reader if nil
+	random io.Reader
 }
 
 // NewUniformRange returns an instance of the UniformRange sampler.
@@ -36,10 +39,25 @@ func NewUniformRange(min, max *big.Int) *UniformRange {
 	}
 }
 
+// NewUniformRangeFromReader returns an instance of the UniformRange
+// sampler that reads randomness from the provided reader instead of
+// crypto/rand. It accepts lower and upper bounds on the sampled values.
+func NewUniformRangeFromReader(min, max *big.Int, random io.Reader) *UniformRange {
+	return &UniformRange{
+		min:    min,
+		max:    max,
+		random: random,
+	}
+}
+
 // Sample samples random values from the interval [min, max).
 func (u *UniformRange) Sample() (*big.Int, error) {
+	random := u.random
+	if random == nil {
+		random = rand.Reader
+	}
 	maxMinusMin := new(big.Int).Sub(u.max, u.min)
-	res, err := rand.Int(rand.Reader, maxMinusMin)
+	res, err := rand.Int(random, maxMinusMin)
 	if err != nil {
 		return nil, err
 	}